@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/log"
+
+	"go.uber.org/zap"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+	BackendNone   = "none"
+)
+
+// Backend stores encoded entries
+type Backend interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+}
+
+var (
+	backendOnce = &sync.Once{}
+	backend     Backend
+	backendName string
+)
+
+func currentBackend() Backend {
+	backendOnce.Do(func() {
+		backendName = config.Config.Cache.Backend
+		if backendName == "" {
+			backendName = BackendMemory
+			if config.Config.RedisAvaliable {
+				backendName = BackendRedis
+			}
+		}
+		switch backendName {
+		case BackendRedis:
+			if !config.Config.RedisAvaliable {
+				log.Logger.Warn("Redis cache backend is not configured, using memory")
+				backendName = BackendMemory
+				backend = newLRUBackend(config.Config.Cache.Size)
+				return
+			}
+			backend = redisBackend{}
+		case BackendNone:
+			backend = noneBackend{}
+		case BackendMemory:
+			backend = newLRUBackend(config.Config.Cache.Size)
+		default:
+			log.Logger.Warn("Unknown cache backend, using memory", zap.String("backend", backendName))
+			backendName = BackendMemory
+			backend = newLRUBackend(config.Config.Cache.Size)
+		}
+	})
+	return backend
+}
+
+// shared reports whether the cache is shared with other processes through Redis
+func shared() bool {
+	currentBackend()
+	return backendName == BackendRedis
+}
+
+type noneBackend struct{}
+
+func (noneBackend) Get(key string) ([]byte, bool) { return nil, false }
+
+func (noneBackend) Set(key string, value []byte, ttl time.Duration) error { return nil }
+
+func (noneBackend) Delete(key string) error { return nil }
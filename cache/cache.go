@@ -0,0 +1,132 @@
+// Package cache caches values in Redis, in process or nowhere, depending on config.Config.Cache.
+//
+//	detail, err := cache.GetOrLoad(cache.NamespaceIGDBGame.Key(1942), cache.DefaultTTL, func() (*model.IGDBGameDetail, error) {
+//		return GetIGDBAppDetail(1942)
+//	})
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/log"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultTTL is how long crawled metadata is cached
+const DefaultTTL = 7 * 24 * time.Hour
+
+// loads coalesces concurrent loads of the same key in this process
+var loads singleflight.Group
+
+// entry is the stored form of a value, At is when the value was loaded and Exp when it expires,
+// it is kept for the stale window after that
+type entry[T any] struct {
+	Value T        `json:"v"`
+	Tags  []string `json:"tags,omitempty"`
+	At    int64    `json:"at"`
+	Exp   int64    `json:"exp"`
+}
+
+// GetOrLoad returns the cached value of key, or loads, caches and returns it.
+// The value is dropped after ttl or once one of tags is invalidated. Load errors are not cached.
+func GetOrLoad[T any](key string, ttl time.Duration, load func() (T, error), tags ...string) (T, error) {
+	return GetOrLoadTagged(key, ttl, func() (T, []string, error) {
+		value, err := load()
+		return value, tags, err
+	})
+}
+
+// GetOrLoadTagged is GetOrLoad for values whose tags depend on the value,
+// e.g. the IDs of the documents in a search result.
+// Concurrent callers of a missing key share one load. An expired value is
+// returned as is for config.Config.Cache.Stale seconds while it is reloaded in the background.
+func GetOrLoadTagged[T any](key string, ttl time.Duration, load func() (T, []string, error)) (T, error) {
+	value, fresh, ok := lookup[T](key)
+	if ok {
+		if !fresh {
+			go func() {
+				if _, err := loadShared(key, ttl, load); err != nil {
+					log.Logger.Warn("Failed to refresh cache", zap.String("key", key), zap.Error(err))
+				}
+			}()
+		}
+		return value, nil
+	}
+	return loadShared(key, ttl, load)
+}
+
+func loadShared[T any](key string, ttl time.Duration, load func() (T, []string, error)) (T, error) {
+	res, err, _ := loads.Do(key, func() (any, error) {
+		at := time.Now()
+		value, tags, err := load()
+		if err != nil {
+			return value, err
+		}
+		store(key, value, ttl, tags, at)
+		return value, nil
+	})
+	if value, ok := res.(T); ok || err != nil {
+		return value, err
+	}
+	// a caller of another type shares the key, load on our own
+	value, tags, err := load()
+	if err != nil {
+		return value, err
+	}
+	store(key, value, ttl, tags, time.Now())
+	return value, nil
+}
+
+// Load returns the cached value of key if it has not expired, values that cannot be decoded as T are missing
+func Load[T any](key string) (T, bool) {
+	value, fresh, ok := lookup[T](key)
+	return value, ok && fresh
+}
+
+// lookup returns the cached value of key and whether it has not expired yet
+func lookup[T any](key string) (T, bool, bool) {
+	var e entry[T]
+	data, ok := currentBackend().Get(key)
+	if !ok {
+		return e.Value, false, false
+	}
+	if err := json.Unmarshal(data, &e); err != nil || e.At == 0 {
+		return e.Value, false, false
+	}
+	// invalidated values are never served, even stale
+	if TagsInvalidatedSince(e.Tags, time.Unix(0, e.At)) {
+		return e.Value, false, false
+	}
+	return e.Value, e.Exp == 0 || time.Now().UnixNano() < e.Exp, true
+}
+
+// Store caches value for ttl, loadedAt is when the data it was built from was read,
+// so that invalidations after that drop it
+func Store[T any](key string, value T, ttl time.Duration, loadedAt time.Time, tags ...string) {
+	store(key, value, ttl, tags, loadedAt)
+}
+
+func store[T any](key string, value T, ttl time.Duration, tags []string, at time.Time) {
+	now := time.Now()
+	data, err := json.Marshal(entry[T]{Value: value, Tags: tags, At: at.UnixNano(), Exp: now.Add(ttl).UnixNano()})
+	if err != nil {
+		return
+	}
+	_ = currentBackend().Set(key, data, ttl+staleWindow())
+}
+
+func staleWindow() time.Duration {
+	if config.Config.Cache.Stale <= 0 {
+		return 0
+	}
+	return time.Duration(config.Config.Cache.Stale) * time.Second
+}
+
+// Delete drops the cached value of key
+func Delete(key string) error {
+	return currentBackend().Delete(key)
+}
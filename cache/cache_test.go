@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nitezs/pcgamedb/config"
+)
+
+// useMemoryBackend points the package at an empty memory backend and forgets the invalidated tags
+func useMemoryBackend(t *testing.T, stale int) {
+	t.Helper()
+	oldOnce, oldBackend, oldName := backendOnce, backend, backendName
+	oldStale := config.Config.Cache.Stale
+	backendOnce = &sync.Once{}
+	backendOnce.Do(func() {})
+	backend, backendName = newLRUBackend(100), BackendMemory
+	config.Config.Cache.Stale = stale
+	tagMutx.Lock()
+	tagTimes = map[string]int64{}
+	tagMutx.Unlock()
+	t.Cleanup(func() {
+		backendOnce, backend, backendName = oldOnce, oldBackend, oldName
+		config.Config.Cache.Stale = oldStale
+	})
+}
+
+// counter is a load function returning how often it was called
+type counter struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (c *counter) load() (int32, error) {
+	n := c.calls.Add(1)
+	return n, c.err
+}
+
+func TestGetOrLoad(t *testing.T) {
+	errLoad := errors.New("load failed")
+	tests := []struct {
+		name string
+		err  error
+		tags []string
+		// between runs after the first load
+		between   func()
+		wantCalls int32
+	}{
+		{name: "cached", wantCalls: 1},
+		{name: "errors are not cached", err: errLoad, wantCalls: 2},
+		{name: "tag invalidated", tags: []string{TagGameInfos}, between: func() { InvalidateTags(TagGameInfos) }, wantCalls: 2},
+		{name: "document invalidated", tags: []string{DocTag("1"), DocTag("2")}, between: func() { InvalidateTags(DocTag("2")) }, wantCalls: 2},
+		{name: "other tag invalidated", tags: []string{TagGameInfos}, between: func() { InvalidateTags(TagGameItems, DocTag("1")) }, wantCalls: 1},
+		{name: "deleted", between: func() { _ = Delete("key") }, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMemoryBackend(t, 0)
+			c := &counter{err: tt.err}
+			first, err := GetOrLoad("key", time.Minute, c.load, tt.tags...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if tt.between != nil {
+				tt.between()
+			}
+			second, err := GetOrLoad("key", time.Minute, c.load, tt.tags...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got := c.calls.Load(); got != tt.wantCalls {
+				t.Errorf("got %d loads, want %d", got, tt.wantCalls)
+			}
+			if tt.wantCalls == 1 && second != first {
+				t.Errorf("got %d, want the cached %d", second, first)
+			}
+		})
+	}
+}
+
+func TestGetOrLoadInvalidatedBeforeLoad(t *testing.T) {
+	useMemoryBackend(t, 0)
+	InvalidateTags(TagGameInfos)
+	c := &counter{}
+	for i := 0; i < 2; i++ {
+		if _, err := GetOrLoad("key", time.Minute, c.load, TagGameInfos); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := c.calls.Load(); got != 1 {
+		t.Errorf("got %d loads, want 1, the value was loaded after the invalidation", got)
+	}
+}
+
+func TestGetOrLoadStale(t *testing.T) {
+	tests := []struct {
+		name  string
+		stale int
+		// want is the value returned once the first one expired
+		want int32
+	}{
+		{name: "stale value served while refreshing", stale: 60, want: 1},
+		{name: "no stale window", stale: 0, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMemoryBackend(t, tt.stale)
+			c := &counter{}
+			if _, err := GetOrLoad("key", time.Millisecond, c.load); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(5 * time.Millisecond)
+			got, err := GetOrLoad("key", time.Minute, c.load)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			// the stale value is replaced by the background refresh
+			deadline := time.Now().Add(time.Second)
+			for {
+				if value, ok := Load[int32]("key"); ok && value == 2 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("value was not refreshed, %d loads", c.calls.Load())
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestGetOrLoadShared(t *testing.T) {
+	useMemoryBackend(t, 0)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func() (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = GetOrLoad("key", time.Minute, load)
+		}(i)
+	}
+	// let the callers reach the shared load before it returns
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d loads, want 1", got)
+	}
+	for _, result := range results {
+		if result != "value" {
+			t.Errorf("got %q, want value", result)
+		}
+	}
+}
+
+func TestLRUBackend(t *testing.T) {
+	b := newLRUBackend(2)
+	_ = b.Set("a", []byte("1"), time.Minute)
+	_ = b.Set("b", []byte("2"), time.Minute)
+	// reading a makes b the least recently used
+	b.Get("a")
+	_ = b.Set("c", []byte("3"), time.Minute)
+	expired := newLRUBackend(2)
+	_ = expired.Set("expired", []byte("4"), -time.Second)
+	if _, ok := expired.Get("expired"); ok {
+		t.Errorf("got an expired entry")
+	}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "a", want: true},
+		{key: "b"},
+		{key: "c", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if _, ok := b.Get(tt.key); ok != tt.want {
+				t.Errorf("got %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
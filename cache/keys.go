@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaVersion prefixes every key, bump it to drop everything cached by older builds at once
+const SchemaVersion = 1
+
+// Namespace is a kind of cached value. Keys of different namespaces never collide,
+// and Version is part of the key, so bumping it when the cached type changes
+// makes older entries unreachable instead of decoding them into the new type.
+type Namespace struct {
+	Name    string
+	Version int
+}
+
+// Key returns the key of the value identified by parts in the namespace
+func (n Namespace) Key(parts ...any) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "pcgamedb:v%d:%s:v%d", SchemaVersion, n.Name, n.Version)
+	for _, part := range parts {
+		sb.WriteByte(':')
+		fmt.Fprint(&sb, part)
+	}
+	return sb.String()
+}
+
+// The namespaces of all keys, each is used by one function only
+var (
+	// name -> IGDB ID
+	NamespaceIGDBID = Namespace{Name: "igdb_id", Version: 1}
+	// name -> failed IGDB ID lookup
+	NamespaceIGDBIDNegative = Namespace{Name: "igdb_id_negative", Version: 1}
+	// IGDB ID -> model.IGDBGameDetail
+	NamespaceIGDBGame = Namespace{Name: "igdb_game", Version: 1}
+	// IGDB company ID -> name
+	NamespaceIGDBCompany = Namespace{Name: "igdb_company", Version: 1}
+	// search query -> model.IGDBSearches
+	NamespaceIGDBSearch = Namespace{Name: "igdb_search", Version: 1}
+	// Steam app ID -> IGDB ID
+	NamespaceIGDBIDBySteamID = Namespace{Name: "igdb_id_by_steam_id", Version: 1}
+	// name -> Steam app ID
+	NamespaceSteamID = Namespace{Name: "steam_id", Version: 1}
+	// name -> failed Steam app ID lookup
+	NamespaceSteamIDNegative = Namespace{Name: "steam_id_negative", Version: 1}
+	// search query -> []crawler.SteamSearchResult
+	NamespaceSteamSearch = Namespace{Name: "steam_search", Version: 1}
+	// Steam app ID -> model.SteamAppDetail
+	NamespaceSteamGame = Namespace{Name: "steam_game", Version: 1}
+	// IGDB ID -> Steam app ID
+	NamespaceSteamIDByIGDBID = Namespace{Name: "steam_id_by_igdb_id", Version: 1}
+	// ranking -> []primitive.ObjectID of game infos
+	NamespaceSteam250 = Namespace{Name: "steam250", Version: 2}
+	// query -> page of []*model.GameInfo
+	NamespaceSearch = Namespace{Name: "search_game_infos", Version: 1}
+	// request path and query -> response
+	NamespaceHTTPResponse = Namespace{Name: "http_response", Version: 1}
+	// tag -> invalidation time
+	NamespaceTag = Namespace{Name: "tag", Version: 1}
+	// bucket, client and window -> request count
+	NamespaceRateLimit = Namespace{Name: "rate_limit", Version: 1}
+	// API key and day -> request count
+	NamespaceQuota = Namespace{Name: "api_key_quota", Version: 1}
+)
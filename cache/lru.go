@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruBackend keeps the most recently used entries in process
+type lruBackend struct {
+	mutx    sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func newLRUBackend(size int) *lruBackend {
+	if size <= 0 {
+		size = 10000
+	}
+	return &lruBackend{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (b *lruBackend) Get(key string) ([]byte, bool) {
+	b.mutx.Lock()
+	defer b.mutx.Unlock()
+	elem, ok := b.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		b.order.Remove(elem)
+		delete(b.entries, key)
+		return nil, false
+	}
+	b.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (b *lruBackend) Set(key string, value []byte, ttl time.Duration) error {
+	b.mutx.Lock()
+	defer b.mutx.Unlock()
+	expiresAt := time.Now().Add(ttl)
+	if elem, ok := b.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		b.order.MoveToFront(elem)
+		return nil
+	}
+	b.entries[key] = b.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for b.order.Len() > b.size {
+		oldest := b.order.Back()
+		b.order.Remove(oldest)
+		delete(b.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+func (b *lruBackend) Delete(key string) error {
+	b.mutx.Lock()
+	defer b.mutx.Unlock()
+	if elem, ok := b.entries[key]; ok {
+		b.order.Remove(elem)
+		delete(b.entries, key)
+	}
+	return nil
+}
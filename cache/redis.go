@@ -59,26 +59,70 @@ func HealthCheck() error {
 	return nil
 }
 
-func Get(key string) (string, bool) {
+type redisBackend struct{}
+
+func (redisBackend) Get(key string) ([]byte, bool) {
 	CheckConnect()
-	ctx := context.Background()
-	value, err := cache.Get(ctx, key).Result()
+	value, err := cache.Get(context.Background(), key).Bytes()
 	if err != nil {
-		return "", false
+		return nil, false
 	}
 	return value, true
 }
 
-func Add(key string, value interface{}) error {
+func (redisBackend) Set(key string, value []byte, ttl time.Duration) error {
+	CheckConnect()
+	return cache.Set(context.Background(), key, value, ttl).Err()
+}
+
+func (redisBackend) Delete(key string) error {
+	CheckConnect()
+	return cache.Del(context.Background(), key).Err()
+}
+
+// Incr increments the counter at key, the counter expires after expire from its first increment
+func Incr(key string, expire time.Duration) (int64, error) {
 	CheckConnect()
 	ctx := context.Background()
-	cmd := cache.Set(ctx, key, value, 7*24*time.Hour)
-	return cmd.Err()
+	pipe := cache.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, expire)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
 }
 
-func AddWithExpire(key string, value interface{}, expire time.Duration) error {
+func Publish(channel string, message interface{}) error {
 	CheckConnect()
 	ctx := context.Background()
-	cmd := cache.Set(ctx, key, value, expire)
-	return cmd.Err()
+	return cache.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe returns the messages published to channel until ctx is done
+func Subscribe(ctx context.Context, channel string) <-chan string {
+	CheckConnect()
+	pubsub := cache.Subscribe(ctx, channel)
+	res := make(chan string)
+	go func() {
+		defer close(res)
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case res <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return res
 }
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tags let cached entries be dropped when a document they were built from changes.
+// An entry records when it was built, it is stale once one of its tags was invalidated after that.
+const (
+	TagGameInfos = "game_infos"
+	TagGameItems = "game_items"
+)
+
+const (
+	// tagRetention is how long invalidations are remembered, longer than any cached entry lives
+	tagRetention = 24 * time.Hour
+)
+
+var (
+	tagMutx    = &sync.Mutex{}
+	tagTimes   = map[string]int64{}
+	tagSweepAt time.Time
+)
+
+// DocTag is the tag of the entries that contain the document with the given ID
+func DocTag(id string) string {
+	return "doc:" + id
+}
+
+// InvalidateTags marks the entries tagged with any of tags as stale
+func InvalidateTags(tags ...string) {
+	if len(tags) == 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	if shared() {
+		CheckConnect()
+		ctx := context.Background()
+		pipe := cache.Pipeline()
+		for _, tag := range tags {
+			pipe.Set(ctx, NamespaceTag.Key(tag), now, tagRetention)
+		}
+		_, _ = pipe.Exec(ctx)
+		return
+	}
+	tagMutx.Lock()
+	defer tagMutx.Unlock()
+	for _, tag := range tags {
+		tagTimes[tag] = now
+	}
+	if time.Now().After(tagSweepAt) {
+		expired := time.Now().Add(-tagRetention).UnixNano()
+		for tag, t := range tagTimes {
+			if t < expired {
+				delete(tagTimes, tag)
+			}
+		}
+		tagSweepAt = time.Now().Add(time.Hour)
+	}
+}
+
+// TagsInvalidatedSince reports whether one of tags was invalidated after since.
+// Errors count as invalidated, so a failing Redis does not serve stale entries.
+func TagsInvalidatedSince(tags []string, since time.Time) bool {
+	if len(tags) == 0 {
+		return false
+	}
+	sinceNano := since.UnixNano()
+	if shared() {
+		CheckConnect()
+		keys := make([]string, len(tags))
+		for i, tag := range tags {
+			keys[i] = NamespaceTag.Key(tag)
+		}
+		values, err := cache.MGet(context.Background(), keys...).Result()
+		if err != nil {
+			return true
+		}
+		for _, value := range values {
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+			t, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || t >= sinceNano {
+				return true
+			}
+		}
+		return false
+	}
+	tagMutx.Lock()
+	defer tagMutx.Unlock()
+	for _, tag := range tags {
+		if tagTimes[tag] >= sinceNano {
+			return true
+		}
+	}
+	return false
+}
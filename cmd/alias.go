@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Long:  "Manage the name aliases learned from manual organizing",
+	Short: "Manage name aliases",
+}
+
+type aliasCommandConfig struct {
+	File      string
+	Overwrite bool
+	Name      string
+}
+
+var aliasCmdCfg aliasCommandConfig
+
+var aliasExportCmd = &cobra.Command{
+	Use:   "export",
+	Long:  "Export the alias table to a JSON file that other instances can import",
+	Short: "Export name aliases",
+	Run: func(cmd *cobra.Command, args []string) {
+		aliases, err := db.GetAllNameAliases()
+		if err != nil {
+			log.Logger.Error("Failed to get aliases", zap.Error(err))
+			return
+		}
+		data, err := json.MarshalIndent(aliases, "", "  ")
+		if err != nil {
+			log.Logger.Error("Failed to marshal aliases", zap.Error(err))
+			return
+		}
+		if err := os.WriteFile(aliasCmdCfg.File, data, 0644); err != nil {
+			log.Logger.Error("Failed to write aliases", zap.Error(err))
+			return
+		}
+		log.Logger.Info("Exported aliases", zap.String("file", aliasCmdCfg.File), zap.Int("count", len(aliases)))
+	},
+}
+
+var aliasImportCmd = &cobra.Command{
+	Use:   "import",
+	Long:  "Import aliases exported by `alias export` or by GET /alias of another instance",
+	Short: "Import name aliases",
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := os.ReadFile(aliasCmdCfg.File)
+		if err != nil {
+			log.Logger.Error("Failed to read aliases", zap.Error(err))
+			return
+		}
+		var aliases []*model.NameAlias
+		if err := json.Unmarshal(data, &aliases); err != nil {
+			// the response of GET /alias
+			var res struct {
+				Aliases []*model.NameAlias `json:"aliases"`
+			}
+			if err := json.Unmarshal(data, &res); err != nil {
+				log.Logger.Error("Failed to unmarshal aliases", zap.Error(err))
+				return
+			}
+			aliases = res.Aliases
+		}
+		count, err := crawler.ImportAliases(aliases, aliasCmdCfg.Overwrite)
+		if err != nil {
+			log.Logger.Error("Failed to import aliases", zap.Error(err))
+			return
+		}
+		log.Logger.Info("Imported aliases", zap.Int("count", count), zap.Int("total", len(aliases)))
+	},
+}
+
+var aliasDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Long:  "Forget the alias of a game name",
+	Short: "Delete a name alias",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := crawler.AliasName(aliasCmdCfg.Name)
+		if err := db.DeleteNameAlias(name); err != nil {
+			log.Logger.Error("Failed to delete alias", zap.String("name", name), zap.Error(err))
+			return
+		}
+		log.Logger.Info("Deleted alias", zap.String("name", name))
+	},
+}
+
+func init() {
+	aliasExportCmd.Flags().StringVarP(&aliasCmdCfg.File, "file", "f", "aliases.json", "output JSON file")
+	aliasImportCmd.Flags().StringVarP(&aliasCmdCfg.File, "file", "f", "aliases.json", "JSON file to import")
+	aliasImportCmd.Flags().BoolVar(&aliasCmdCfg.Overwrite, "overwrite", false, "replace the aliases of names that already have one")
+	aliasDeleteCmd.Flags().StringVarP(&aliasCmdCfg.Name, "name", "n", "", "game name")
+	_ = aliasDeleteCmd.MarkFlagRequired("name")
+	aliasCmd.AddCommand(aliasExportCmd, aliasImportCmd, aliasDeleteCmd)
+	RootCmd.AddCommand(aliasCmd)
+}
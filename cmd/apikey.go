@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Long:  "Manage API keys",
+	Short: "Manage API keys",
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Long:  "Create an API key, the key is only printed once",
+	Short: "Create an API key",
+	Run:   apiKeyCreateRun,
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id|prefix>",
+	Long:  "Revoke an API key by ID or prefix",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run:   apiKeyRevokeRun,
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Long:  "List API keys",
+	Short: "List API keys",
+	Run:   apiKeyListRun,
+}
+
+type apiKeyCommandConfig struct {
+	Owner      string
+	Name       string
+	Scopes     string
+	Expires    time.Duration
+	DailyQuota int
+}
+
+var apiKeyCmdCfg apiKeyCommandConfig
+
+func init() {
+	apiKeyCreateCmd.Flags().StringVarP(&apiKeyCmdCfg.Owner, "owner", "o", "", "owner of the key")
+	apiKeyCreateCmd.Flags().StringVarP(&apiKeyCmdCfg.Name, "name", "n", "", "name of the key")
+	apiKeyCreateCmd.Flags().StringVarP(&apiKeyCmdCfg.Scopes, "scopes", "s", model.ScopeRead, fmt.Sprintf("comma separated scopes (%s)", strings.Join(model.Scopes, ",")))
+	apiKeyCreateCmd.Flags().DurationVarP(&apiKeyCmdCfg.Expires, "expires", "e", 0, "lifetime of the key, e.g. 720h, no expiry by default")
+	apiKeyCreateCmd.Flags().IntVarP(&apiKeyCmdCfg.DailyQuota, "quota", "q", 0, "requests allowed per day, no limit by default")
+	apiKeyCmd.AddCommand(apiKeyCreateCmd, apiKeyRevokeCmd, apiKeyListCmd)
+	RootCmd.AddCommand(apiKeyCmd)
+}
+
+func apiKeyCreateRun(cmd *cobra.Command, args []string) {
+	if apiKeyCmdCfg.Owner == "" {
+		log.Logger.Error("Owner is required")
+		return
+	}
+	if strings.EqualFold(strings.TrimSpace(apiKeyCmdCfg.Owner), model.AdminOwner) {
+		log.Logger.Error("Owner is reserved for the server secret key", zap.String("owner", apiKeyCmdCfg.Owner))
+		return
+	}
+	var scopes []string
+	for _, scope := range strings.Split(apiKeyCmdCfg.Scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !slices.Contains(model.Scopes, scope) {
+			log.Logger.Error("Invalid scope", zap.String("scope", scope))
+			return
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		log.Logger.Error("At least one scope is required")
+		return
+	}
+	apiKey := &model.APIKey{
+		Owner:      apiKeyCmdCfg.Owner,
+		Name:       apiKeyCmdCfg.Name,
+		Scopes:     scopes,
+		DailyQuota: apiKeyCmdCfg.DailyQuota,
+	}
+	if apiKeyCmdCfg.Expires > 0 {
+		expiresAt := time.Now().Add(apiKeyCmdCfg.Expires)
+		apiKey.ExpiresAt = &expiresAt
+	}
+	key, err := db.CreateAPIKey(apiKey)
+	if err != nil {
+		log.Logger.Error("Failed to create API key", zap.Error(err))
+		return
+	}
+	log.Logger.Info("API key created",
+		zap.String("id", apiKey.ID.Hex()),
+		zap.String("owner", apiKey.Owner),
+		zap.Strings("scopes", apiKey.Scopes),
+	)
+	// printed instead of logged, so the key does not end up in the log files
+	fmt.Println(key)
+}
+
+func apiKeyRevokeRun(cmd *cobra.Command, args []string) {
+	apiKey, err := db.RevokeAPIKey(args[0])
+	if err != nil {
+		log.Logger.Error("Failed to revoke API key", zap.String("key", args[0]), zap.Error(err))
+		return
+	}
+	log.Logger.Info("API key revoked",
+		zap.String("id", apiKey.ID.Hex()),
+		zap.String("prefix", apiKey.Prefix),
+		zap.String("owner", apiKey.Owner),
+	)
+}
+
+func apiKeyListRun(cmd *cobra.Command, args []string) {
+	keys, err := db.GetAllAPIKeys()
+	if err != nil {
+		log.Logger.Error("Failed to get API keys", zap.Error(err))
+		return
+	}
+	for _, key := range keys {
+		fields := []zap.Field{
+			zap.String("id", key.ID.Hex()),
+			zap.String("prefix", key.Prefix),
+			zap.String("owner", key.Owner),
+			zap.String("name", key.Name),
+			zap.Strings("scopes", key.Scopes),
+			zap.Int("daily_quota", key.DailyQuota),
+		}
+		if key.ExpiresAt != nil {
+			fields = append(fields, zap.Time("expires_at", *key.ExpiresAt))
+		}
+		if key.RevokedAt != nil {
+			fields = append(fields, zap.Time("revoked_at", *key.RevokedAt))
+		}
+		if key.LastUsedAt != nil {
+			fields = append(fields, zap.Time("last_used_at", *key.LastUsedAt))
+		}
+		log.Logger.Info("API key", fields...)
+	}
+}
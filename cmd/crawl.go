@@ -8,7 +8,9 @@ import (
 
 	"github.com/nitezs/pcgamedb/crawler"
 	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/notify"
 	"github.com/nitezs/pcgamedb/utils"
+	"github.com/nitezs/pcgamedb/webhook"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -56,6 +58,11 @@ func init() {
 }
 
 func crawlRun(cmd *cobra.Command, args []string) {
+	// deliveries the process does not finish are retried by the server
+	webhook.Init()
+	defer webhook.Wait()
+	notify.Init()
+	defer notify.Wait()
 	crawlCmdCfg.Source = strings.ToLower(crawlCmdCfg.Source)
 
 	if crawlCmdCfg.Source == "" {
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
-	"github.com/nitezs/pcgamedb/crawler"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/normalize"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -14,124 +16,72 @@ import (
 var formatCmd = &cobra.Command{
 	Use:   "format",
 	Short: "Format game downloads name by formatter",
-	Long:  "Format game downloads name by formatter",
+	Long:  "Format game downloads name by the name rules of their source, or explain which rules change a name",
 	Run:   formatRun,
 }
 
 type FormatCommandConfig struct {
-	Source string
+	Source  string
+	Explain string
 }
 
 var formatCmdCfg FormatCommandConfig
 
 func init() {
-	formatCmd.Flags().StringVarP(&formatCmdCfg.Source, "source", "s", "", "source to fix (fitgirl/dodi/kaoskrew/freegog/xatab/onlinefix/armgddn)")
+	formatCmd.Flags().StringVarP(&formatCmdCfg.Source, "source", "s", "", "source to fix ("+strings.Join(normalize.Sources(), "/")+")")
+	formatCmd.Flags().StringVarP(&formatCmdCfg.Explain, "explain", "e", "", "show the rules that change this raw name, with the rules of --source or the generic ones")
 	RootCmd.AddCommand(formatCmd)
 }
 
 func formatRun(cmd *cobra.Command, args []string) {
-	formatCmdCfg.Source = strings.ToLower(formatCmdCfg.Source)
-	switch formatCmdCfg.Source {
-	case "dodi":
-		items, err := db.GetDODIGameItems()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.DODIFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameItem(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "kaoskrew":
-		items, err := db.GetKaOsKrewGameItems()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.KaOsKrewFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameItem(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "freegog":
-		items, err := db.GetFreeGOGGameItems()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.FreeGOGFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameItem(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "xatab":
-		items, err := db.GetXatabGameItems()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.XatabFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameItem(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "onlinefix":
-		items, err := db.GetOnlineFixGameItems()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.OnlineFixFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameItem(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "armgddn":
-		items, err := db.GetARMGDDNGameItems()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
+	if formatCmdCfg.Explain != "" {
+		formatExplain(formatCmdCfg.Explain)
+		return
+	}
+	formatSource()
+}
+
+func formatExplain(raw string) {
+	n := normalize.Generic
+	if formatCmdCfg.Source != "" {
+		var ok bool
+		if n, ok = normalize.For(formatCmdCfg.Source); !ok {
+			log.Logger.Error("Unknown source", zap.String("source", formatCmdCfg.Source))
 			return
 		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.ARMGDDNFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameItem(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
+	}
+	name, steps := n.Explain(raw)
+	fmt.Printf("%s rules\n", n.Name)
+	fmt.Printf("  raw    %q\n", raw)
+	for _, step := range steps {
+		fmt.Printf("  %-26s %q\n", step.Rule, step.After)
+	}
+	if len(steps) == 0 {
+		fmt.Println("  no rule fired")
+	}
+	fmt.Printf("  result %q\n", name)
+}
+
+func formatSource() {
+	source := strings.ToLower(formatCmdCfg.Source)
+	n, ok := normalize.For(source)
+	if !ok || n == normalize.Generic {
+		log.Logger.Error("Unknown source", zap.String("source", formatCmdCfg.Source))
+		return
+	}
+	items, err := db.GetGameItemsByAuthor("^" + regexp.QuoteMeta(source) + "$")
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	for _, item := range items {
+		oldName := item.Name
+		item.Name = n.Normalize(item.RawName)
+		if oldName != item.Name {
+			log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
+			err := db.SaveGameItem(item)
+			if err != nil {
+				log.Logger.Error("Failed to update item", zap.Error(err))
 			}
 		}
 	}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/server"
+	"github.com/nitezs/pcgamedb/server/openapi"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var openapiCmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Generate or verify the OpenAPI spec of the v2 API",
+	Long:  "Generate the OpenAPI spec of the v2 API, or verify that the committed spec and the registered routes match the handlers",
+	Run:   openapiRun,
+}
+
+type openapiCommandConfig struct {
+	Output string
+	Verify bool
+}
+
+var openapiCmdCfg openapiCommandConfig
+
+func init() {
+	openapiCmd.Flags().StringVarP(&openapiCmdCfg.Output, "output", "o", "server/openapi/v2.json", "output file, - for stdout")
+	openapiCmd.Flags().BoolVar(&openapiCmdCfg.Verify, "verify", false, "verify the committed spec and routes instead of generating")
+	RootCmd.AddCommand(openapiCmd)
+}
+
+func openapiRun(cmd *cobra.Command, args []string) {
+	if openapiCmdCfg.Verify {
+		errs := server.VerifyV2()
+		for _, err := range errs {
+			log.Logger.Error("Contract violation", zap.Error(err))
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		log.Logger.Info("OpenAPI spec matches the v2 routes")
+		return
+	}
+	data, err := openapi.Marshal(server.V2Spec())
+	if err != nil {
+		log.Logger.Error("Failed to generate OpenAPI spec", zap.Error(err))
+		return
+	}
+	if openapiCmdCfg.Output == "-" {
+		fmt.Print(string(data))
+		return
+	}
+	if err := os.WriteFile(openapiCmdCfg.Output, data, 0644); err != nil {
+		log.Logger.Error("Failed to write OpenAPI spec", zap.Error(err))
+		return
+	}
+	log.Logger.Info("OpenAPI spec written", zap.String("file", openapiCmdCfg.Output))
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"errors"
+
 	"github.com/nitezs/pcgamedb/crawler"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/log"
@@ -34,14 +36,19 @@ func organizeRun(cmd *cobra.Command, args []string) {
 	}
 	for _, game := range games {
 		gameInfo, err := crawler.OrganizeGameItem(game)
-		if err == nil {
-			err = db.SaveGameInfo(gameInfo)
-			if err != nil {
-				log.Logger.Error("Failed to save game info", zap.Error(err))
+		if err != nil {
+			if errors.Is(err, crawler.ErrNeedsReview) {
+				log.Logger.Info("Game needs review", zap.String("name", game.Name), zap.Error(err))
 				continue
 			}
-			log.Logger.Info("Organized game", zap.String("name", game.Name))
+			log.Logger.Error("Failed to organize game", zap.String("name", game.Name), zap.Error(err))
+			continue
+		}
+		err = db.SaveGameInfo(gameInfo)
+		if err != nil {
+			log.Logger.Error("Failed to save game info", zap.Error(err))
+			continue
 		}
-		log.Logger.Error("Failed to organize game", zap.String("name", game.Name))
+		log.Logger.Info("Organized game", zap.String("name", game.Name))
 	}
 }
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var organizeTUICmd = &cobra.Command{
+	Use:   "tui",
+	Long:  "Walk the unorganized games in the terminal, search IGDB and Steam for each one and link it with a keypress",
+	Short: "Organize unorganized games interactively",
+	Run:   organizeTUIRun,
+}
+
+type organizeTUICommandConfig struct {
+	Num     int
+	State   string
+	Skipped bool
+}
+
+var organizeTUICmdCfg organizeTUICommandConfig
+
+func init() {
+	organizeTUICmd.Flags().IntVarP(&organizeTUICmdCfg.Num, "num", "n", -1, "number of items to load")
+	organizeTUICmd.Flags().StringVarP(&organizeTUICmdCfg.State, "state", "s", "organize_state.json", "file remembering skips and decisions")
+	organizeTUICmd.Flags().BoolVar(&organizeTUICmdCfg.Skipped, "skipped", false, "show the items skipped before again")
+	organizeCmd.AddCommand(organizeTUICmd)
+}
+
+// organizeDecision is what was decided for a game item, Platform is empty when no game matches it
+type organizeDecision struct {
+	Name       string    `json:"name"`
+	Platform   string    `json:"platform,omitempty"`
+	PlatformID int       `json:"platform_id,omitempty"`
+	DecidedAt  time.Time `json:"decided_at"`
+}
+
+// organizeState is kept in a file so that later runs do not show the same items again
+type organizeState struct {
+	Skipped   map[string]time.Time        `json:"skipped"`
+	Decisions map[string]organizeDecision `json:"decisions"`
+}
+
+func loadOrganizeState(path string) (*organizeState, error) {
+	state := &organizeState{Skipped: map[string]time.Time{}, Decisions: map[string]organizeDecision{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Skipped == nil {
+		state.Skipped = map[string]time.Time{}
+	}
+	if state.Decisions == nil {
+		state.Decisions = map[string]organizeDecision{}
+	}
+	return state, nil
+}
+
+// save writes the state to a temporary file first so that an interrupted write keeps the old state
+func (s *organizeState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+type organizeTUI struct {
+	keys       *keyReader
+	state      *organizeState
+	games      []*model.GameItem
+	index      int
+	query      string
+	candidates []model.MatchCandidate
+	searchErr  error
+	status     string
+	linked     int
+}
+
+func organizeTUIRun(cmd *cobra.Command, args []string) {
+	state, err := loadOrganizeState(organizeTUICmdCfg.State)
+	if err != nil {
+		log.Logger.Error("Failed to load state", zap.String("path", organizeTUICmdCfg.State), zap.Error(err))
+		return
+	}
+	items, err := db.GetUnorganizedGameItems(organizeTUICmdCfg.Num)
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	var games []*model.GameItem
+	for _, game := range items {
+		id := game.ID.Hex()
+		if _, ok := state.Decisions[id]; ok {
+			continue
+		}
+		if _, ok := state.Skipped[id]; ok && !organizeTUICmdCfg.Skipped {
+			continue
+		}
+		games = append(games, game)
+	}
+	if len(games) == 0 {
+		fmt.Println("No unorganized games left")
+		return
+	}
+	t := &organizeTUI{keys: newKeyReader(), state: state, games: games}
+	defer t.keys.Close()
+	t.run()
+}
+
+func (t *organizeTUI) run() {
+	for t.index < len(t.games) {
+		game := t.games[t.index]
+		if t.candidates == nil && t.searchErr == nil {
+			t.draw(game, "Searching IGDB and Steam...")
+			t.candidates, t.searchErr = crawler.SearchCandidates(game, t.query)
+			if t.candidates == nil {
+				t.candidates = []model.MatchCandidate{}
+			}
+		}
+		t.draw(game, "")
+		key, err := t.keys.ReadKey()
+		if err != nil {
+			break
+		}
+		switch {
+		case key == 'q':
+			fmt.Println()
+			return
+		case key == '/':
+			fmt.Print("\nSearch: ")
+			query, err := t.keys.ReadLine()
+			if err != nil {
+				return
+			}
+			t.search(query)
+		case key == 'p':
+			if t.index > 0 {
+				t.index--
+				t.reset()
+			}
+		case key == 's':
+			t.state.Skipped[game.ID.Hex()] = time.Now()
+			t.next(fmt.Sprintf("Skipped %s", game.Name))
+		case key == 'n':
+			t.decide(game, organizeDecision{Name: game.Name, DecidedAt: time.Now()})
+			t.next(fmt.Sprintf("No match for %s", game.Name))
+		case key >= '1' && key <= '9' && int(key-'1') < len(t.candidates):
+			t.link(game, t.candidates[key-'1'])
+		}
+	}
+	t.draw(nil, "")
+	fmt.Printf("No more games, %d linked\n", t.linked)
+}
+
+func (t *organizeTUI) link(game *model.GameItem, candidate model.MatchCandidate) {
+	info, err := crawler.OrganizeGameItemManually(game.ID, candidate.Platform, candidate.PlatformID)
+	if err == nil {
+		err = db.SaveGameInfo(info)
+	}
+	if err != nil {
+		t.status = fmt.Sprintf("Failed to link %s: %v", game.Name, err)
+		return
+	}
+	t.linked++
+	t.decide(game, organizeDecision{
+		Name:       game.Name,
+		Platform:   candidate.Platform,
+		PlatformID: candidate.PlatformID,
+		DecidedAt:  time.Now(),
+	})
+	t.next(fmt.Sprintf("Linked %s to %s", game.Name, info.Name))
+}
+
+func (t *organizeTUI) decide(game *model.GameItem, decision organizeDecision) {
+	id := game.ID.Hex()
+	delete(t.state.Skipped, id)
+	t.state.Decisions[id] = decision
+}
+
+// next saves the state and moves to the next game
+func (t *organizeTUI) next(status string) {
+	if err := t.state.save(organizeTUICmdCfg.State); err != nil {
+		status = fmt.Sprintf("Failed to save state: %v", err)
+	}
+	t.index++
+	t.reset()
+	t.status = status
+}
+
+func (t *organizeTUI) search(query string) {
+	t.query = query
+	t.candidates = nil
+	t.searchErr = nil
+}
+
+func (t *organizeTUI) reset() {
+	t.search("")
+	t.status = ""
+}
+
+func (t *organizeTUI) draw(game *model.GameItem, message string) {
+	width := terminalWidth()
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	fmt.Fprintf(&b, "pcgamedb organize  %d/%d  %d linked\n", t.index+1, len(t.games), t.linked)
+	b.WriteString(strings.Repeat("─", width) + "\n")
+	if game == nil {
+		fmt.Print(b.String())
+		return
+	}
+	fmt.Fprintf(&b, "Raw name  %s\n", truncate(game.RawName, width-10))
+	fmt.Fprintf(&b, "Author    %s   Size %s\n", game.Author, game.Size)
+	if t.query != "" {
+		fmt.Fprintf(&b, "Search    %s\n", t.query)
+	}
+	if decision, ok := t.state.Decisions[game.ID.Hex()]; ok {
+		if decision.Platform == "" {
+			b.WriteString("Decided   no match\n")
+		} else {
+			fmt.Fprintf(&b, "Decided   %s %d\n", decision.Platform, decision.PlatformID)
+		}
+	}
+	b.WriteString("\n")
+	nameWidth := max(20, width-52)
+	row := func(n string, platform string, id string, year string, developer string, confidence string, name string) {
+		fmt.Fprintf(&b, "%-2s %-5s %-8s %-4s  %-20s %-4s  %s\n", n, platform, id, year, truncate(developer, 20), confidence, truncate(name, nameWidth))
+	}
+	row("", "", "", "Year", "Developer", "Conf", "Name")
+	year := ""
+	if y := crawler.ReleaseYear(game.RawName); y != 0 {
+		year = fmt.Sprint(y)
+	}
+	row("", "item", "", year, "", "", game.Name)
+	for i, c := range t.candidates {
+		if i == 9 {
+			break
+		}
+		year := ""
+		if c.Year != 0 {
+			year = fmt.Sprint(c.Year)
+		}
+		row(fmt.Sprint(i+1), c.Platform, fmt.Sprint(c.PlatformID), year, strings.Join(c.Developers, ", "), fmt.Sprintf("%.2f", c.Confidence), c.Name)
+	}
+	if t.candidates != nil && len(t.candidates) == 0 {
+		b.WriteString("   no candidates found\n")
+	}
+	if t.searchErr != nil {
+		fmt.Fprintf(&b, "   %s\n", truncate(t.searchErr.Error(), width-3))
+	}
+	b.WriteString(strings.Repeat("─", width) + "\n")
+	if message == "" {
+		message = t.status
+	}
+	if message != "" {
+		b.WriteString(truncate(message, width) + "\n")
+	}
+	b.WriteString("[1-9] link  [/] search  [s] skip  [n] no match  [p] previous  [q] quit")
+	fmt.Print(b.String())
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 || len(r) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Long:  "Walk the review queue and accept or reject the candidates of each game with one keystroke",
+	Short: "Walk the review queue",
+	Run:   reviewRun,
+}
+
+type reviewCommandConfig struct {
+	Reviewer string
+}
+
+var reviewCmdCfg reviewCommandConfig
+
+func init() {
+	reviewCmd.Flags().StringVarP(&reviewCmdCfg.Reviewer, "reviewer", "r", "cli", "name recorded as the reviewer")
+	RootCmd.AddCommand(reviewCmd)
+}
+
+func reviewRun(cmd *cobra.Command, args []string) {
+	keys := newKeyReader()
+	defer keys.Close()
+	page := &db.Page{Limit: 20}
+	done := 0
+	for {
+		reviews, _, total, err := db.GetMatchReviewsPage(model.MatchReviewPending, page)
+		if err != nil {
+			log.Logger.Error("Failed to get reviews", zap.Error(err))
+			return
+		}
+		if len(reviews) == 0 {
+			fmt.Printf("No more pending reviews, %d decided\n", done)
+			return
+		}
+		// total is counted before this page, so subtract what was decided since
+		pageStart := done
+		for _, review := range reviews {
+			printReview(review, total-int64(done-pageStart))
+			switch decision := promptReview(keys, review); decision {
+			case 'q':
+				fmt.Printf("%d decided\n", done)
+				return
+			case 's':
+				fmt.Println("Skipped")
+			case 'r':
+				if err := crawler.RejectMatchReview(review, reviewCmdCfg.Reviewer); err != nil {
+					log.Logger.Error("Failed to reject review", zap.String("name", review.Name), zap.Error(err))
+					continue
+				}
+				done++
+				fmt.Println("Rejected")
+			default:
+				chosen := review.Candidates[decision-'1']
+				info, err := crawler.AcceptMatchReview(review, chosen, reviewCmdCfg.Reviewer)
+				if err != nil {
+					log.Logger.Error("Failed to accept review", zap.String("name", review.Name), zap.Error(err))
+					continue
+				}
+				done++
+				fmt.Printf("Linked to %s\n", info.Name)
+			}
+		}
+		last := reviews[len(reviews)-1].ID
+		page = &db.Page{After: &db.Cursor{ID: &last}, Limit: 20}
+	}
+}
+
+func printReview(review *model.MatchReview, pending int64) {
+	fmt.Printf("\n%s (%d pending)\n", review.Name, pending)
+	fmt.Printf("  raw name: %s\n", review.RawName)
+	fmt.Printf("  author:   %s\n", review.Author)
+	fmt.Printf("  reason:   %s\n", review.Reason)
+	if review.Error != "" {
+		fmt.Printf("  error:    %s\n", review.Error)
+	}
+	for i, candidate := range review.Candidates {
+		if i >= 9 {
+			break
+		}
+		fmt.Printf("  %d) %-5s %-9d %.2f  %s\n", i+1, candidate.Platform, candidate.PlatformID, candidate.Confidence, candidate.Name)
+		if len(candidate.Reasons) > 0 {
+			fmt.Printf("     %s\n", strings.Join(candidate.Reasons, ", "))
+		}
+	}
+}
+
+// promptReview reads keys until one is a valid decision: a candidate number, r, s or q
+func promptReview(keys *keyReader, review *model.MatchReview) byte {
+	choices := min(len(review.Candidates), 9)
+	if choices > 0 {
+		fmt.Printf("[1-%d] accept  [r] reject  [s] skip  [q] quit: ", choices)
+	} else {
+		fmt.Print("[r] reject  [s] skip  [q] quit: ")
+	}
+	for {
+		key, err := keys.ReadKey()
+		if err != nil {
+			fmt.Println()
+			return 'q'
+		}
+		switch {
+		case key == 'r', key == 's', key == 'q':
+			fmt.Printf("%c\n", key)
+			return key
+		case key >= '1' && int(key-'0') <= choices:
+			fmt.Printf("%c\n", key)
+			return key
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/log"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var steamAppsCmd = &cobra.Command{
+	Use:   "steam-apps",
+	Long:  "Manage the local Steam app list used to resolve Steam IDs",
+	Short: "Manage the local Steam app list",
+}
+
+type steamAppsImportCommandConfig struct {
+	File string
+}
+
+var steamAppsImportCmdCfg steamAppsImportCommandConfig
+
+var steamAppsImportCmd = &cobra.Command{
+	Use:   "import",
+	Long:  "Replace the local Steam app list with a GetAppList JSON file, or with the list fetched from the Steam API",
+	Short: "Import the Steam app list",
+	Run: func(cmd *cobra.Command, args []string) {
+		count, err := crawler.ImportSteamApps(steamAppsImportCmdCfg.File)
+		if err != nil {
+			log.Logger.Error("Failed to import Steam app list", zap.Error(err))
+			return
+		}
+		log.Logger.Info("Imported Steam app list", zap.Int("count", count))
+	},
+}
+
+type steamAppsMatchCommandConfig struct {
+	Name string
+}
+
+var steamAppsMatchCmdCfg steamAppsMatchCommandConfig
+
+var steamAppsMatchCmd = &cobra.Command{
+	Use:   "match",
+	Long:  "Resolve a name against the local Steam app list only",
+	Short: "Resolve a name against the Steam app list",
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := crawler.GetSteamIDFromApps(steamAppsMatchCmdCfg.Name)
+		if err != nil {
+			log.Logger.Error("Failed to match name", zap.String("name", steamAppsMatchCmdCfg.Name), zap.Error(err))
+			return
+		}
+		log.Logger.Info("Matched name", zap.String("name", steamAppsMatchCmdCfg.Name), zap.Int("steam_id", id))
+	},
+}
+
+func init() {
+	steamAppsImportCmd.Flags().StringVarP(&steamAppsImportCmdCfg.File, "file", "f", "", "GetAppList JSON file")
+	steamAppsMatchCmd.Flags().StringVarP(&steamAppsMatchCmdCfg.Name, "name", "n", "", "game name")
+	_ = steamAppsMatchCmd.MarkFlagRequired("name")
+	steamAppsCmd.AddCommand(steamAppsImportCmd, steamAppsMatchCmd)
+	RootCmd.AddCommand(steamAppsCmd)
+}
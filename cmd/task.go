@@ -26,6 +26,10 @@ var taskCmd = &cobra.Command{
 			if err != nil {
 				log.Logger.Error("Failed to add task", zap.Error(err))
 			}
+			_, err = c.AddFunc("30 4 * * *", func() { task.ImportSteamApps(log.Logger) })
+			if err != nil {
+				log.Logger.Error("Failed to add task", zap.Error(err))
+			}
 			c.Start()
 			select {}
 		}
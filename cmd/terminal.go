@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// keyReader reads single keystrokes from the terminal. When stdin is not a terminal
+// or stty is missing it falls back to reading the first character of each line.
+type keyReader struct {
+	in      *bufio.Reader
+	state   string
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+func newKeyReader() *keyReader {
+	r := &keyReader{in: bufio.NewReader(os.Stdin)}
+	state, err := stty("-g")
+	if err != nil {
+		return r
+	}
+	if _, err := stty("cbreak", "-echo"); err != nil {
+		return r
+	}
+	r.state = strings.TrimSpace(state)
+	r.signals = make(chan os.Signal, 1)
+	r.done = make(chan struct{})
+	signal.Notify(r.signals, os.Interrupt, syscall.SIGTERM)
+	go r.restoreOnSignal()
+	return r
+}
+
+// restoreOnSignal restores the terminal when the command is interrupted,
+// deferred calls do not run then and the shell would be left without echo
+func (r *keyReader) restoreOnSignal() {
+	select {
+	case sig := <-r.signals:
+		_, _ = stty(r.state)
+		fmt.Println()
+		code := 1
+		if s, ok := sig.(syscall.Signal); ok {
+			code = 128 + int(s)
+		}
+		os.Exit(code)
+	case <-r.done:
+	}
+}
+
+func (r *keyReader) ReadKey() (byte, error) {
+	if r.state != "" {
+		return r.in.ReadByte()
+	}
+	line, err := r.in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if err != nil {
+			return 0, err
+		}
+		return '\n', nil
+	}
+	return line[0], nil
+}
+
+// ReadLine reads a whole line with echo, for free text input between keystrokes
+func (r *keyReader) ReadLine() (string, error) {
+	if r.state != "" {
+		_, _ = stty(r.state)
+		defer stty("cbreak", "-echo")
+	}
+	line, err := r.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Close restores the terminal state
+func (r *keyReader) Close() {
+	if r.state != "" {
+		signal.Stop(r.signals)
+		close(r.done)
+		_, _ = stty(r.state)
+	}
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// terminalWidth returns the number of columns of the terminal, or 100 if unknown
+func terminalWidth() int {
+	size, err := stty("size")
+	if err != nil {
+		return 100
+	}
+	var rows, cols int
+	if _, err := fmt.Sscan(size, &rows, &cols); err != nil || cols <= 0 {
+		return 100
+	}
+	return cols
+}
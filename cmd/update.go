@@ -38,7 +38,7 @@ func updateRun(cmd *cobra.Command, args []string) {
 		log.Logger.Error("Failed to parse game info id", zap.Error(err))
 		return
 	}
-	oldInfo, err := db.GetGameInfoByID(id)
+	oldInfo, err := db.GetGameInfoByID(id, nil)
 	if err != nil {
 		log.Logger.Error("Failed to get game info", zap.Error(err))
 		return
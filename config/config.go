@@ -19,6 +19,11 @@ type config struct {
 	OnlineFix          onlinefix `json:"online_fix"`
 	Twitch             twitch    `json:"twitch"`
 	Webhooks           webhooks  `json:"webhooks"`
+	RateLimit          rateLimit `json:"rate_limit"`
+	Cache              cache     `json:"cache"`
+	Organize           organize  `json:"organize"`
+	Notify             notify    `json:"notify"`
+	SteamAppList       string    `env:"STEAM_APP_LIST" json:"steam_app_list"`
 	DatabaseAvaliable  bool
 	OnlineFixAvaliable bool
 	MegaAvaliable      bool
@@ -26,13 +31,54 @@ type config struct {
 }
 
 type webhooks struct {
+	// Deprecated: CrawlTask URLs get the crawled items in an unsigned POST after each crawl task.
+	// Subscribe a webhook to item-created and crawl-finished with POST /webhook instead, its deliveries are signed and retried.
 	CrawlTask []string `env:"WEBHOOKS_CRAWL_TASK" json:"crawl_task"`
 }
 
+type notify struct {
+	// TelegramAPIURL is the Bot API server of the Telegram notifiers, https://api.telegram.org by default
+	TelegramAPIURL string `env:"NOTIFY_TELEGRAM_API_URL" json:"telegram_api_url"`
+}
+
+type organize struct {
+	// MinConfidence is the confidence in percent a match needs to be linked without review
+	MinConfidence int `env:"ORGANIZE_MIN_CONFIDENCE" json:"min_confidence"`
+}
+
+type cache struct {
+	// Backend is redis, memory or none, redis when Redis is configured and memory otherwise by default
+	Backend string `env:"CACHE_BACKEND" json:"backend"`
+	// Size is the number of entries kept by the memory backend
+	Size int `env:"CACHE_SIZE" json:"size"`
+	// Stale is how many seconds an expired entry is still served while it is refreshed in the background
+	Stale int `env:"CACHE_STALE" json:"stale"`
+	// Negative is how many seconds a name that does not resolve to an IGDB or Steam ID is not looked up again
+	Negative int `env:"CACHE_NEGATIVE" json:"negative"`
+	// NegativeError is Negative for lookups that failed with an API error
+	NegativeError int `env:"CACHE_NEGATIVE_ERROR" json:"negative_error"`
+}
+
+// rateLimit limits the requests of each client per window, 0 disables a bucket
+type rateLimit struct {
+	// Window is the length of a window in seconds
+	Window int `env:"RATE_LIMIT_WINDOW" json:"window"`
+	Search int `env:"RATE_LIMIT_SEARCH" json:"search"`
+	Detail int `env:"RATE_LIMIT_DETAIL" json:"detail"`
+	// KeyMultiplier multiplies the limits of requests made with a valid API key
+	KeyMultiplier int `env:"RATE_LIMIT_KEY_MULTIPLIER" json:"key_multiplier"`
+	// Connections is the number of event streams a client may keep open at once, 0 for no limit
+	Connections int `env:"RATE_LIMIT_CONNECTIONS" json:"connections"`
+}
+
 type server struct {
 	Port      string `env:"SERVER_PORT" json:"port"`
 	SecretKey string `env:"SERVER_SECRET_KEY" json:"secret_key"`
 	AutoCrawl bool   `env:"SERVER_AUTO_CRAWL" json:"auto_crawl"`
+	// PublicURL is the URL clients reach the server at, used for the links in feeds
+	PublicURL string `env:"SERVER_PUBLIC_URL" json:"public_url"`
+	// TrustedProxies are the addresses or CIDRs whose X-Forwarded-For header is used as the client IP, none by default
+	TrustedProxies []string `env:"SERVER_TRUSTED_PROXIES" json:"trusted_proxies"`
 }
 
 type database struct {
@@ -75,6 +121,22 @@ func init() {
 			User:     "root",
 			Password: "password",
 		},
+		Organize: organize{
+			MinConfidence: 75,
+		},
+		Cache: cache{
+			Size:          10000,
+			Stale:         86400,
+			Negative:      86400,
+			NegativeError: 600,
+		},
+		RateLimit: rateLimit{
+			Window:        60,
+			Search:        30,
+			Detail:        120,
+			KeyMultiplier: 10,
+			Connections:   5,
+		},
 		MegaAvaliable: TestMega(),
 	}
 	if _, err := os.Stat("config.json"); err == nil {
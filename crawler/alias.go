@@ -0,0 +1,122 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
+	"github.com/nitezs/pcgamedb/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Sources of name aliases
+const (
+	AliasSourceManual = "manual"
+	AliasSourceReview = "review"
+	AliasSourceImport = "import"
+)
+
+// AliasName returns the name game items are looked up by in the alias table,
+// items of any source with the same alias name are the same game
+func AliasName(name string) string {
+	return utils.NormalizeForIndex(normalize.Generic.Normalize(name))
+}
+
+// LearnAlias remembers that game is the game platformID of platform,
+// so that items with the same name are linked to it without searching
+func LearnAlias(game *model.GameItem, platform string, platformID int, source string) error {
+	name := AliasName(game.Name)
+	if name == "" {
+		return nil
+	}
+	return db.SaveNameAlias(&model.NameAlias{
+		Name:       name,
+		GameName:   game.Name,
+		Platform:   platform,
+		PlatformID: platformID,
+		Source:     source,
+	})
+}
+
+// learnAliasOfGame is LearnAlias for a game item known by ID, failures are only logged
+// because the organize they come from succeeded
+func learnAliasOfGame(gameID primitive.ObjectID, platform string, platformID int, source string) {
+	game, err := db.GetGameItemByID(gameID)
+	if err == nil {
+		err = LearnAlias(game, platform, platformID, source)
+	}
+	if err != nil {
+		log.Logger.Warn("Failed to learn alias", zap.String("game_id", gameID.Hex()), zap.Error(err))
+	}
+}
+
+// organizeGameItemWithAlias links game to the game of its alias, it returns mongo.ErrNoDocuments if there is none
+func organizeGameItemWithAlias(game *model.GameItem) (*model.GameInfo, error) {
+	name := AliasName(game.Name)
+	if name == "" {
+		return nil, mongo.ErrNoDocuments
+	}
+	alias, err := db.GetNameAlias(name)
+	if err != nil {
+		return nil, err
+	}
+	chosen := model.MatchCandidate{
+		Platform:   alias.Platform,
+		PlatformID: alias.PlatformID,
+		Name:       alias.GameName,
+		Confidence: 1,
+		Reasons:    []string{fmt.Sprintf("alias %q learned from %s", alias.Name, alias.Source)},
+	}
+	info, err := LinkGameItem(game, chosen, nil)
+	if err != nil {
+		return nil, err
+	}
+	info.Matches[len(info.Matches)-1].Chosen.Name = info.Name
+	_ = db.ResolveMatchReviewOfGame(game.ID, "alias")
+	return info, nil
+}
+
+// ImportAliases adds aliases exported by another instance. Names are normalized again,
+// so aliases exported by a version with other name rules still match. Aliases of names
+// that already have one are kept unless overwrite is set. It returns the number of aliases added or changed.
+func ImportAliases(aliases []*model.NameAlias, overwrite bool) (int, error) {
+	now := time.Now()
+	index := map[string]int{}
+	var valid []*model.NameAlias
+	for _, alias := range aliases {
+		if alias.GameName != "" {
+			alias.Name = AliasName(alias.GameName)
+		}
+		if alias.Name == "" || alias.PlatformID <= 0 || (alias.Platform != "igdb" && alias.Platform != "steam") {
+			continue
+		}
+		if alias.Source == "" {
+			alias.Source = AliasSourceImport
+		}
+		if alias.CreatedAt.IsZero() {
+			alias.CreatedAt = now
+		}
+		alias.UpdatedAt = now
+		// the last alias of a name wins, as it would have when saved one by one
+		if i, ok := index[alias.Name]; ok {
+			valid[i] = alias
+			continue
+		}
+		index[alias.Name] = len(valid)
+		valid = append(valid, alias)
+	}
+	if len(valid) == 0 {
+		if len(aliases) != 0 {
+			return 0, errors.New("no valid aliases")
+		}
+		return 0, nil
+	}
+	return db.SaveNameAliases(valid, overwrite)
+}
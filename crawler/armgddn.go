@@ -6,13 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/url"
-	"regexp"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/jlaffaye/ftp"
@@ -121,7 +120,7 @@ func (c *ARMGDDNCrawler) crawlGames(data []GameData, platform string, num int) (
 			continue
 		}
 		item.Url = u
-		item.Name = ARMGDDNFormatter(v.FolderName)
+		item.Name = normalize.ARMGDDN.Normalize(v.FolderName)
 		item.UpdateFlag = updateFlag
 		item.Size = utils.FormatSize(size)
 		item.RawName = v.FolderName
@@ -159,15 +158,6 @@ func (c *ARMGDDNCrawler) crawlGames(data []GameData, platform string, num int) (
 	return res, nil
 }
 
-func ARMGDDNFormatter(name string) string {
-	cleanedName := strings.ReplaceAll(strings.TrimSpace(name), "-ARMGDDN", "")
-	matchIndex := regexp.MustCompile(`v\d`).FindStringIndex(cleanedName)
-	if matchIndex == nil {
-		return cleanedName
-	}
-	return strings.TrimSpace(cleanedName[:matchIndex[0]])
-}
-
 func (c *ARMGDDNCrawler) CrawlPC(num int) ([]*model.GameItem, error) {
 	return c.crawlPlatform("/PC/currentserverPC-FTP.json", "PC", num)
 }
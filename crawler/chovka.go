@@ -5,11 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
@@ -47,7 +47,7 @@ func (c *ChovkaCrawler) CrawlByUrl(url string) (*model.GameItem, error) {
 	}
 	item.Url = url
 	item.RawName = doc.Find(".inner-entry__title").First().Text()
-	item.Name = ChovkaFormatter(item.RawName)
+	item.Name = normalize.Chovka.Normalize(item.RawName)
 	item.Author = "Chovka"
 	item.UpdateFlag = item.RawName
 	downloadURL := doc.Find(".download-torrent").AttrOr("href", "")
@@ -166,19 +166,3 @@ func (c *ChovkaCrawler) GetTotalPageNum() (int, error) {
 	}
 	return totalPageNum, nil
 }
-
-func ChovkaFormatter(name string) string {
-	idx := strings.Index(name, "| RePack")
-	if idx != -1 {
-		name = name[:idx]
-	}
-	idx = strings.Index(name, "| GOG")
-	if idx != -1 {
-		name = name[:idx]
-	}
-	idx = strings.Index(name, "| Portable")
-	if idx != -1 {
-		name = name[:idx]
-	}
-	return strings.TrimSpace(name)
-}
@@ -1,11 +1,8 @@
 package crawler
 
 import (
-	"regexp"
-	"strings"
-
 	"github.com/nitezs/pcgamedb/model"
-	"github.com/nitezs/pcgamedb/utils"
+	"github.com/nitezs/pcgamedb/normalize"
 
 	"go.uber.org/zap"
 )
@@ -22,7 +19,7 @@ func NewDODICrawler(logger *zap.Logger) *DODICrawler {
 		logger: logger,
 		crawler: *New1337xCrawler(
 			DODIName,
-			DODIFormatter,
+			normalize.DODI.Normalize,
 			logger,
 		),
 	}
@@ -51,53 +48,3 @@ func (c *DODICrawler) CrawlAll() ([]*model.GameItem, error) {
 func (c *DODICrawler) GetTotalPageNum() (int, error) {
 	return c.crawler.GetTotalPageNum()
 }
-
-var dodiRegexps = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\s{2,}`),
-	regexp.MustCompile(`(?i)[\-\+]\s?[^:\-]*?\s(Edition|Bundle|Pack|Set|Remake|Collection)`),
-}
-
-func DODIFormatter(name string) string {
-	name = strings.Replace(name, "- [DODI Repack]", "", -1)
-	name = strings.Replace(name, "- Campaign Remastered", "", -1)
-	name = strings.Replace(name, "- Remastered", "", -1)
-	if index := strings.Index(name, "+"); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "–"); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "("); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "["); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "- AiO"); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "- All In One"); index != -1 {
-		name = name[:index]
-	}
-	for _, re := range dodiRegexps {
-		name = strings.TrimSpace(re.ReplaceAllString(name, ""))
-	}
-	name = strings.TrimSpace(name)
-	name = strings.Replace(name, "- Portable", "", -1)
-	name = strings.Replace(name, "- Remastered", "", -1)
-
-	if index := strings.Index(name, "/"); index != -1 {
-		names := strings.Split(name, "/")
-		longestLength := 0
-		longestName := ""
-		for _, n := range names {
-			if !utils.ContainsRussian(n) && len(n) > longestLength {
-				longestLength = len(n)
-				longestName = n
-			}
-		}
-		name = longestName
-	}
-
-	return strings.TrimSpace(name)
-}
@@ -10,6 +10,7 @@ import (
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
@@ -106,7 +107,7 @@ func (c *FreeGOGCrawler) CrawlByUrl(url string) (*model.GameItem, error) {
 	} else {
 		return nil, err
 	}
-	item.Name = FreeGOGFormatter(item.RawName)
+	item.Name = normalize.FreeGOG.Normalize(item.RawName)
 	sizeRegex := regexp.MustCompile(`(?i)>Size:\s?(.*?)<`)
 	sizeRegexRes := sizeRegex.FindStringSubmatch(string(resp.Data))
 	if len(sizeRegexRes) > 1 {
@@ -130,26 +131,3 @@ func (c *FreeGOGCrawler) CrawlByUrl(url string) (*model.GameItem, error) {
 func (c *FreeGOGCrawler) CrawlAll() ([]*model.GameItem, error) {
 	return c.Crawl(-1)
 }
-
-var freeGOGRegexps = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\(.*\)`),
-}
-
-func FreeGOGFormatter(name string) string {
-	for _, re := range freeGOGRegexps {
-		name = re.ReplaceAllString(name, "")
-	}
-
-	reg1 := regexp.MustCompile(`(?i)v\d+(\.\d+)*`)
-	if index := reg1.FindIndex([]byte(name)); index != nil {
-		name = name[:index[0]]
-	}
-	if index := strings.Index(name, "+"); index != -1 {
-		name = name[:index]
-	}
-
-	reg2 := regexp.MustCompile(`(?i):\sgoty`)
-	name = reg2.ReplaceAllString(name, ": Game Of The Year")
-
-	return strings.TrimSpace(name)
-}
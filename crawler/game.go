@@ -2,11 +2,12 @@ package crawler
 
 import (
 	"errors"
-	"regexp"
-	"strings"
+	"fmt"
 	"time"
 
+	"github.com/nitezs/pcgamedb/config"
 	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
 	"github.com/nitezs/pcgamedb/model"
 	"github.com/nitezs/pcgamedb/utils"
 	"go.uber.org/zap"
@@ -26,29 +27,83 @@ func GenerateGameInfo(platform string, id int) (*model.GameInfo, error) {
 	}
 }
 
+// OrganizeGameItem links game to the game info of its alias, or of its best IGDB or Steam match.
+// Matches below config.Config.Organize.MinConfidence and names no game was found for
+// are queued for review and ErrNeedsReview is returned, as it is for game items already in the queue.
+// Lookups that failed with an API error are not queued, they are retried by the next organize.
 func OrganizeGameItem(game *model.GameItem) (*model.GameInfo, error) {
-	item, err := OrganizeGameItemWithIGDB(0, game)
+	info, err := organizeGameItemWithAlias(game)
 	if err == nil {
-		if item.SteamID == 0 {
+		return info, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Logger.Warn("Failed to organize game with alias", zap.String("name", game.Name), zap.Error(err))
+	}
+	// accepted reviews were linked once, items unlinked later are matched again
+	if review, err := db.GetMatchReviewByGameID(game.ID); err == nil &&
+		(review.Status == model.MatchReviewPending || review.Status == model.MatchReviewRejected) {
+		return nil, fmt.Errorf("%w: %s is %s", ErrNeedsReview, game.Name, review.Status)
+	}
+	candidates, err := MatchGameItem(game)
+	if err != nil {
+		reason := reviewReason(err)
+		if reason == "" {
+			return nil, err
+		}
+		if err := queueReview(game, reason, err, nil); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrNeedsReview, err)
+	}
+	best := candidates[0]
+	if best.Confidence*100 < float64(config.Config.Organize.MinConfidence) {
+		if err := queueReview(game, model.ReviewReasonLowConfidence, nil, append([]model.MatchCandidate{best}, RunnerUps(candidates)...)); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s matched %s with confidence %.2f", ErrNeedsReview, game.Name, best.Name, best.Confidence)
+	}
+	return LinkGameItem(game, best, RunnerUps(candidates))
+}
+
+// LinkGameItem returns the game info of chosen with game linked to it and the match recorded, it does not save it
+func LinkGameItem(game *model.GameItem, chosen model.MatchCandidate, runnerUps []model.MatchCandidate) (*model.GameInfo, error) {
+	var info *model.GameInfo
+	var err error
+	switch chosen.Platform {
+	case "igdb":
+		info, err = OrganizeGameItemWithIGDB(chosen.PlatformID, game)
+		if err == nil && info.SteamID == 0 {
 			// get steam id from igdb
-			steamID, err := GetSteamIDByIGDBIDCache(item.IGDBID)
-			if err == nil {
-				item.SteamID = steamID
+			if steamID, err := GetSteamIDByIGDBIDCache(info.IGDBID); err == nil {
+				info.SteamID = steamID
 			}
-			return item, nil
 		}
-	}
-	item, err = OrganizeGameItemWithSteam(0, game)
-	if err == nil {
-		if item.IGDBID == 0 {
-			igdbID, err := GetIGDBIDBySteamIDCache(item.SteamID)
-			if err == nil {
-				item.IGDBID = igdbID
+	case "steam":
+		info, err = OrganizeGameItemWithSteam(chosen.PlatformID, game)
+		if err == nil && info.IGDBID == 0 {
+			if igdbID, err := GetIGDBIDBySteamIDCache(info.SteamID); err == nil {
+				info.IGDBID = igdbID
 			}
 		}
-		return item, nil
+	default:
+		return nil, errors.New("Invalid ID type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	matches := info.Matches[:0]
+	for _, match := range info.Matches {
+		if match.GameID != game.ID {
+			matches = append(matches, match)
+		}
 	}
-	return nil, err
+	info.Matches = append(matches, model.GameMatch{
+		GameID:    game.ID,
+		Chosen:    chosen,
+		RunnerUps: runnerUps,
+		MatchedAt: time.Now(),
+	})
+	return info, nil
 }
 
 func AddGameInfoManually(gameID primitive.ObjectID, platform string, plateformID int) (*model.GameInfo, error) {
@@ -62,7 +117,7 @@ func AddGameInfoManually(gameID primitive.ObjectID, platform string, plateformID
 }
 
 func OrganizeGameItemManually(gameID primitive.ObjectID, platform string, platformID int) (*model.GameInfo, error) {
-	info, err := db.GetGameInfoByPlatformID(platform, platformID)
+	info, err := db.GetGameInfoByPlatformID(platform, platformID, nil)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			info, err = AddGameInfoManually(gameID, platform, platformID)
@@ -79,6 +134,8 @@ func OrganizeGameItemManually(gameID primitive.ObjectID, platform string, platfo
 	if err != nil {
 		return nil, err
 	}
+	_ = db.ResolveMatchReviewOfGame(gameID, "manual")
+	learnAliasOfGame(gameID, platform, platformID, AliasSourceManual)
 	if platform == "igdb" {
 		steamID, err := GetSteamIDByIGDBIDCache(platformID)
 		if err == nil {
@@ -94,19 +151,6 @@ func OrganizeGameItemManually(gameID primitive.ObjectID, platform string, platfo
 	return info, nil
 }
 
-func FormatName(name string) string {
-	name = regexp.MustCompile(`(?i)[\w’'-]+\s(Edition|Vision|Collection|Bundle|Pack|Deluxe)`).ReplaceAllString(name, " ")
-	name = regexp.MustCompile(`(?i)GOTY`).ReplaceAllString(name, "")
-	name = regexp.MustCompile(`(?i)nsw for pc`).ReplaceAllString(name, "")
-	name = regexp.MustCompile(`\([^\)]+\)`).ReplaceAllString(name, "")
-	name = regexp.MustCompile(`\s+`).ReplaceAllString(name, " ")
-	name = strings.Replace(name, ": Remastered", "", -1)
-	name = strings.Replace(name, ": Remaster", "", -1)
-	name = strings.TrimSpace(name)
-	name = strings.Trim(name, ":")
-	return name
-}
-
 func SupplementPlatformIDToGameInfo(logger *zap.Logger) error {
 	infos, err := db.GetAllGameInfos()
 	if err != nil {
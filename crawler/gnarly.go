@@ -8,6 +8,7 @@ import (
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
@@ -67,7 +68,7 @@ func (c *GnarlyCrawler) Crawl(num int) ([]*model.GameItem, error) {
 						item.RawName = lines[i-1]
 						item.Url = constant.GnarlyURL
 						item.Author = "Gnarly"
-						item.Name = GnarlyFormatter(item.RawName)
+						item.Name = normalize.Gnarly.Normalize(item.RawName)
 						download, err := utils.DecryptPrivateBin(lines[i], "gnarly")
 						if err != nil {
 							continue
@@ -96,11 +97,3 @@ func (c *GnarlyCrawler) Crawl(num int) ([]*model.GameItem, error) {
 func (c *GnarlyCrawler) CrawlAll() ([]*model.GameItem, error) {
 	return c.Crawl(-1)
 }
-
-var parenthesesRegex = regexp.MustCompile(`\(([^)]+)\)`)
-
-func GnarlyFormatter(name string) string {
-	name = name[:strings.Index(name, " [Gnarly Repacks]")]
-	name = parenthesesRegex.ReplaceAllString(name, "")
-	return strings.TrimSpace(name)
-}
@@ -9,23 +9,26 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nitezs/pcgamedb/cache"
 	"github.com/nitezs/pcgamedb/config"
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 )
 
 var TwitchToken string
 
-func _GetIGDBID(name string) (int, error) {
+// SearchIGDB returns the IGDB search results of name on PC platforms, or on any platform if there are none
+func SearchIGDB(name string) (model.IGDBSearches, error) {
 	var err error
 	if TwitchToken == "" {
 		TwitchToken, err = LoginTwitch()
 		if err != nil {
-			return 0, fmt.Errorf("failed to login twitch: %w", err)
+			return nil, fmt.Errorf("failed to login twitch: %w", err)
 		}
 	}
 	resp, err := utils.Fetch(utils.FetchConfig{
@@ -39,7 +42,7 @@ func _GetIGDBID(name string) (int, error) {
 		Data:   fmt.Sprintf(`search "%s"; fields *; limit 50; where game.platforms = [6] | game.platforms=[130] | game.platforms=[384] | game.platforms=[163];`, name),
 		Method: "POST",
 	})
-	if string(resp.Data) == "[]" {
+	if err == nil && string(resp.Data) == "[]" {
 		resp, err = utils.Fetch(utils.FetchConfig{
 			Url: constant.IGDBSearchURL,
 			Headers: map[string]string{
@@ -53,11 +56,31 @@ func _GetIGDBID(name string) (int, error) {
 		})
 	}
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	var data model.IGDBSearches
 	if err = json.Unmarshal(resp.Data, &data); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal: %w, %s", err, debug.Stack())
+		return nil, fmt.Errorf("failed to unmarshal: %w, %s", err, debug.Stack())
+	}
+	return data, nil
+}
+
+// SearchIGDBCache is SearchIGDB with the results cached, so that items organized again
+// do not search for the same names every run. Failed searches are not cached.
+func SearchIGDBCache(name string) (model.IGDBSearches, error) {
+	key := cache.NamespaceIGDBSearch.Key(name)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() (model.IGDBSearches, error) {
+		return SearchIGDB(name)
+	})
+}
+
+func _GetIGDBID(name string) (int, error) {
+	data, err := SearchIGDB(name)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, fmt.Errorf("IGDB: %w: %s", ErrIDNotFound, name)
 	}
 	if len(data) == 1 {
 		return data[0].Game, nil
@@ -79,46 +102,29 @@ func _GetIGDBID(name string) (int, error) {
 			}
 		}
 	}
-	return 0, fmt.Errorf("IGDB ID not found: %s", name)
+	return 0, fmt.Errorf("IGDB: %w: %s", ErrIDAmbiguous, name)
 }
 
 func GetIGDBID(name string) (int, error) {
 	name1 := name
-	name2 := FormatName(name)
+	name2 := normalize.Generic.Normalize(name)
 	names := []string{name1}
 	if name1 != name2 {
 		names = append(names, name2)
 	}
+	errs := make([]error, 0, len(names))
 	for _, name := range names {
 		id, err := _GetIGDBID(name)
 		if err == nil {
 			return id, nil
 		}
+		errs = append(errs, err)
 	}
-	return 0, errors.New("IGDB ID not found")
+	return 0, errors.Join(errs...)
 }
 
 func GetIGDBIDCache(name string) (int, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("igdb_id:%s", name)
-		val, exist := cache.Get(key)
-		if exist {
-			id, err := strconv.Atoi(val)
-			if err != nil {
-				return 0, err
-			}
-			return id, nil
-		} else {
-			id, err := GetIGDBID(name)
-			if err != nil {
-				return 0, err
-			}
-			_ = cache.Add(key, id)
-			return id, nil
-		}
-	} else {
-		return GetIGDBID(name)
-	}
+	return getIDCache("igdb", cache.NamespaceIGDBID, name, GetIGDBID)
 }
 
 func GetIGDBAppDetail(id int) (*model.IGDBGameDetail, error) {
@@ -157,30 +163,10 @@ func GetIGDBAppDetail(id int) (*model.IGDBGameDetail, error) {
 }
 
 func GetIGDBAppDetailCache(id int) (*model.IGDBGameDetail, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("igdb_game:%v", id)
-		val, exist := cache.Get(key)
-		if exist {
-			var data model.IGDBGameDetail
-			if err := json.Unmarshal([]byte(val), &data); err != nil {
-				return nil, err
-			}
-			return &data, nil
-		} else {
-			data, err := GetIGDBAppDetail(id)
-			if err != nil {
-				return nil, err
-			}
-			dataBytes, err := json.Marshal(data)
-			if err != nil {
-				return nil, err
-			}
-			_ = cache.Add(key, dataBytes)
-			return data, nil
-		}
-	} else {
+	key := cache.NamespaceIGDBGame.Key(id)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() (*model.IGDBGameDetail, error) {
 		return GetIGDBAppDetail(id)
-	}
+	})
 }
 
 func LoginTwitch() (string, error) {
@@ -246,22 +232,10 @@ func GetIGDBCompany(id int) (string, error) {
 }
 
 func GetIGDBCompanyCache(id int) (string, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("igdb_companies:%v", id)
-		val, exist := cache.Get(key)
-		if exist {
-			return val, nil
-		} else {
-			data, err := GetIGDBCompany(id)
-			if err != nil {
-				return "", err
-			}
-			_ = cache.Add(key, data)
-			return data, nil
-		}
-	} else {
+	key := cache.NamespaceIGDBCompany.Key(id)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() (string, error) {
 		return GetIGDBCompany(id)
-	}
+	})
 }
 
 func GenerateIGDBGameInfo(id int) (*model.GameInfo, error) {
@@ -320,7 +294,7 @@ func OrganizeGameItemWithIGDB(id int, game *model.GameItem) (*model.GameInfo, er
 			return nil, err
 		}
 	}
-	d, err := db.GetGameInfoByPlatformID("igdb", id)
+	d, err := db.GetGameInfoByPlatformID("igdb", id, nil)
 	if err == nil {
 		d.GameIDs = append(d.GameIDs, game.ID)
 		d.GameIDs = utils.Unique(d.GameIDs)
@@ -373,22 +347,10 @@ func GetIGDBIDBySteamID(id int) (int, error) {
 }
 
 func GetIGDBIDBySteamIDCache(id int) (int, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("igdb_id_by_steam_id:%v", id)
-		val, exist := cache.Get(key)
-		if exist {
-			return strconv.Atoi(val)
-		} else {
-			data, err := GetIGDBIDBySteamID(id)
-			if err != nil {
-				return 0, err
-			}
-			_ = cache.Add(key, strconv.Itoa(data))
-			return data, nil
-		}
-	} else {
+	key := cache.NamespaceIGDBIDBySteamID.Key(id)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() (int, error) {
 		return GetIGDBIDBySteamID(id)
-	}
+	})
 }
 
 func GetIGDBIDsBySteamIDs(ids []int) (map[int]int, error) {
@@ -450,32 +412,27 @@ func GetIGDBIDsBySteamIDs(ids []int) (map[int]int, error) {
 func GetIGDBIDsBySteamIDsCache(ids []int) (map[int]int, error) {
 	res := make(map[int]int)
 	notExistIDs := make([]int, 0)
-	if config.Config.RedisAvaliable {
-		for _, steamID := range ids {
-			key := fmt.Sprintf("igdb_id_by_steam_id:%v", steamID)
-			val, exist := cache.Get(key)
-			if exist {
-				igdbID, _ := strconv.Atoi(val)
-				res[steamID] = igdbID
-			} else {
-				notExistIDs = append(notExistIDs, steamID)
-			}
-		}
-		if len(res) == len(ids) {
-			return res, nil
-		}
-		idMap, err := GetIGDBIDsBySteamIDs(notExistIDs)
-		if err != nil {
-			return nil, err
-		}
-		for steamID, igdbID := range idMap {
+	for _, steamID := range ids {
+		igdbID, exist := cache.Load[int](cache.NamespaceIGDBIDBySteamID.Key(steamID))
+		if exist {
 			res[steamID] = igdbID
-			if igdbID != 0 {
-				_ = cache.Add(fmt.Sprintf("igdb_id_by_steam_id:%v", steamID), igdbID)
-			}
+		} else {
+			notExistIDs = append(notExistIDs, steamID)
 		}
+	}
+	if len(res) == len(ids) {
 		return res, nil
-	} else {
-		return GetIGDBIDsBySteamIDs(ids)
 	}
+	loadedAt := time.Now()
+	idMap, err := GetIGDBIDsBySteamIDs(notExistIDs)
+	if err != nil {
+		return nil, err
+	}
+	for steamID, igdbID := range idMap {
+		res[steamID] = igdbID
+		if igdbID != 0 {
+			cache.Store(cache.NamespaceIGDBIDBySteamID.Key(steamID), igdbID, cache.DefaultTTL, loadedAt)
+		}
+	}
+	return res, nil
 }
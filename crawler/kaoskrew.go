@@ -1,10 +1,8 @@
 package crawler
 
 import (
-	"regexp"
-	"strings"
-
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 
 	"go.uber.org/zap"
 )
@@ -21,7 +19,7 @@ func NewKaOsKrewCrawler(logger *zap.Logger) *KaOsKrewCrawler {
 		logger: logger,
 		crawler: *New1337xCrawler(
 			KaOsKrewName,
-			KaOsKrewFormatter,
+			normalize.KaOsKrew.Normalize,
 			logger,
 		),
 	}
@@ -50,22 +48,3 @@ func (c *KaOsKrewCrawler) CrawlAll() ([]*model.GameItem, error) {
 func (c *KaOsKrewCrawler) GetTotalPageNum() (int, error) {
 	return c.crawler.GetTotalPageNum()
 }
-
-var kaOsKrewRegexps = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\.REPACK2?-KaOs`),
-	regexp.MustCompile(`(?i)\.UPDATE-KaOs`),
-	regexp.MustCompile(`(?i)v\.?\d+(\.\d+)*|Build\.\d+`),
-	regexp.MustCompile(`(?i)\.MULTi\d+`),
-	regexp.MustCompile(`(?i)\sgoty`),
-}
-
-func KaOsKrewFormatter(name string) string {
-	if index := kaOsKrewRegexps[2].FindIndex([]byte(name)); index != nil {
-		name = name[:index[0]]
-	}
-	for _, re := range kaOsKrewRegexps {
-		name = re.ReplaceAllString(name, "")
-	}
-	name = strings.Replace(name, ".", " ", -1)
-	return strings.TrimSpace(name)
-}
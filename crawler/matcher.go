@@ -0,0 +1,413 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
+	"github.com/nitezs/pcgamedb/utils"
+)
+
+const (
+	// name scores below this are not candidates
+	matchMinNameScore = 0.8
+	// candidates of each platform whose details are fetched for the other signals
+	matchDetailCandidates = 5
+	// a different game scoring closer than this to the best one makes the match ambiguous
+	matchMinLead = 0.05
+	// runner-ups kept with a match
+	matchMaxRunnerUps = 3
+)
+
+// ErrNeedsReview is returned by OrganizeGameItem for game items queued for review
+var ErrNeedsReview = errors.New("match needs review")
+
+var releaseYearRegex = regexp.MustCompile(`\b(19[7-9]\d|20[0-9]\d)\b`)
+
+// words that make a game another product than the one named, unless the name has them too
+var editionWords = []string{"soundtrack", "ost", "demo", "dlc", "season pass", "pack", "bundle", "edition", "remastered", "definitive", "collection", "artbook"}
+
+// IGDB categories and Steam types that are not base games
+var (
+	igdbAddonCategories = map[int]bool{1: true, 2: true, 3: true, 5: true, 6: true, 7: true, 13: true, 14: true}
+	steamAddonTypes     = map[string]bool{"dlc": true, "demo": true, "music": true, "video": true, "mod": true, "advertising": true}
+)
+
+type matchCandidate struct {
+	model.MatchCandidate
+	names     []string
+	query     string
+	nameScore float64
+	alias     bool
+	addon     bool
+}
+
+// MatchGameItem scores the IGDB and Steam games found for the name of game, best first
+func MatchGameItem(game *model.GameItem) ([]model.MatchCandidate, error) {
+	queries := matchQueries(game)
+	igdb, igdbErr := igdbCandidates(game.Name, queries)
+	steam, steamErr := steamCandidates(game.Name, queries)
+	candidates := append(igdb, steam...)
+	if len(candidates) == 0 {
+		return nil, errors.Join(igdbErr, steamErr)
+	}
+	sortCandidates(candidates, game)
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if sameGame(best, c) {
+			continue
+		}
+		if best.Confidence-c.Confidence < matchMinLead {
+			best.Confidence = clampConfidence(best.Confidence - 0.1)
+			best.Reasons = append(best.Reasons, fmt.Sprintf("%s is almost as close", c.Name))
+		}
+		break
+	}
+	return unwrapCandidates(candidates), nil
+}
+
+// SearchCandidates searches IGDB, the Steam store and the local Steam app list for query,
+// or for the name of game when query is empty, and scores the results against game, best first.
+// Unlike MatchGameItem it ignores the negative cache and keeps candidates with distant names.
+func SearchCandidates(game *model.GameItem, query string) ([]model.MatchCandidate, error) {
+	queries := []string{query}
+	if query == "" {
+		queries = matchQueries(game)
+	}
+	igdb, igdbErrs := searchIGDBCandidates(queries, true)
+	igdb = closestCandidates(igdb, queries)
+	addIGDBDetails(igdb, queries)
+	steam, steamErrs := searchSteamCandidates(queries, true)
+	steam = closestCandidates(steam, queries)
+	addSteamDetails(steam)
+	candidates := append(igdb, steam...)
+	if len(candidates) == 0 {
+		return nil, errors.Join(append(igdbErrs, steamErrs...)...)
+	}
+	sortCandidates(candidates, game)
+	return unwrapCandidates(candidates), nil
+}
+
+// ReleaseYear returns the release year in a raw game name, or 0
+func ReleaseYear(raw string) int {
+	year, _ := strconv.Atoi(releaseYearRegex.FindString(raw))
+	return year
+}
+
+func matchQueries(game *model.GameItem) []string {
+	queries := []string{game.Name}
+	if formatted := normalize.Generic.Normalize(game.Name); formatted != "" && formatted != game.Name {
+		queries = append(queries, formatted)
+	}
+	return queries
+}
+
+func sortCandidates(candidates []*matchCandidate, game *model.GameItem) {
+	for _, c := range candidates {
+		scoreCandidate(c, game)
+	}
+	// IGDB first on equal confidence, it has the richer metadata
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+}
+
+func unwrapCandidates(candidates []*matchCandidate) []model.MatchCandidate {
+	res := make([]model.MatchCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		res = append(res, c.MatchCandidate)
+	}
+	return res
+}
+
+// RunnerUps returns the candidates after the best one that are not the same game on another platform
+func RunnerUps(candidates []model.MatchCandidate) []model.MatchCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+	best := utils.NormalizeForIndex(candidates[0].Name)
+	var res []model.MatchCandidate
+	for _, c := range candidates[1:] {
+		if len(res) == matchMaxRunnerUps {
+			break
+		}
+		if c.Platform != candidates[0].Platform && utils.NormalizeForIndex(c.Name) == best {
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func sameGame(a *matchCandidate, b *matchCandidate) bool {
+	if a.Platform == b.Platform {
+		return a.PlatformID == b.PlatformID
+	}
+	return utils.NormalizeForIndex(a.Name) == utils.NormalizeForIndex(b.Name)
+}
+
+func igdbCandidates(name string, queries []string) ([]*matchCandidate, error) {
+	if err := loadNegative("igdb", name); err != nil {
+		return nil, err
+	}
+	candidates, errs := searchIGDBCandidates(queries, false)
+	candidates, err := closeCandidates("igdb", name, queries, candidates, errs)
+	if err != nil {
+		return nil, err
+	}
+	addIGDBDetails(candidates, queries)
+	return candidates, nil
+}
+
+func steamCandidates(name string, queries []string) ([]*matchCandidate, error) {
+	if err := loadNegative("steam", name); err != nil {
+		return nil, err
+	}
+	candidates, errs := searchSteamCandidates(queries, false)
+	candidates, err := closeCandidates("steam", name, queries, candidates, errs)
+	if err != nil {
+		return nil, err
+	}
+	addSteamDetails(candidates)
+	return candidates, nil
+}
+
+// searchIGDBCandidates searches IGDB for queries, the cached results are used unless live is set
+func searchIGDBCandidates(queries []string, live bool) ([]*matchCandidate, []error) {
+	search := SearchIGDBCache
+	if live {
+		search = SearchIGDB
+	}
+	byID := map[int]*matchCandidate{}
+	var order []int
+	var errs []error
+	for _, query := range queries {
+		results, err := search(query)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, result := range results {
+			c, ok := byID[result.Game]
+			if !ok {
+				c = &matchCandidate{MatchCandidate: model.MatchCandidate{Platform: "igdb", PlatformID: result.Game, Name: result.Name}}
+				byID[result.Game] = c
+				order = append(order, result.Game)
+			}
+			c.names = append(c.names, result.Name, result.AlternativeName)
+		}
+	}
+	candidates := make([]*matchCandidate, 0, len(order))
+	for _, id := range order {
+		candidates = append(candidates, byID[id])
+	}
+	return candidates, errs
+}
+
+// searchSteamCandidates searches the local app list, and the store when live is set
+// or the app list has nothing close. The cached store results are used unless live is set.
+func searchSteamCandidates(queries []string, live bool) ([]*matchCandidate, []error) {
+	search := SearchSteamCache
+	if live {
+		search = SearchSteam
+	}
+	byID := map[int]*matchCandidate{}
+	var order []int
+	add := func(id int, appName string) {
+		if _, ok := byID[id]; ok {
+			return
+		}
+		byID[id] = &matchCandidate{MatchCandidate: model.MatchCandidate{Platform: "steam", PlatformID: id, Name: appName}, names: []string{appName}}
+		order = append(order, id)
+	}
+	closeEnough := false
+	for _, query := range queries {
+		exact, _ := db.FindSteamAppsByName(query)
+		fuzzy, _ := db.FuzzySearchSteamApps(query, matchDetailCandidates, matchMinNameScore)
+		for _, app := range append(exact, fuzzy...) {
+			add(app.AppID, app.Name)
+			if app.Score >= 0.95 {
+				closeEnough = true
+			}
+		}
+	}
+	var errs []error
+	if live || !closeEnough {
+		for _, query := range queries {
+			results, err := search(query)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for _, result := range results {
+				add(result.ID, result.Name)
+			}
+		}
+	}
+	candidates := make([]*matchCandidate, 0, len(order))
+	for _, id := range order {
+		candidates = append(candidates, byID[id])
+	}
+	return candidates, errs
+}
+
+// addIGDBDetails sets the title, aliases, release year, developers and category of candidates
+func addIGDBDetails(candidates []*matchCandidate, queries []string) {
+	for _, c := range candidates {
+		detail, err := GetIGDBAppDetailCache(c.PlatformID)
+		if err != nil {
+			continue
+		}
+		c.Name = detail.Name
+		c.names = append([]string{detail.Name}, c.names...)
+		for _, alternative := range detail.AlternativeNames {
+			c.names = append(c.names, alternative.Name)
+		}
+		if detail.FirstReleaseDate != 0 {
+			c.Year = time.Unix(int64(detail.FirstReleaseDate), 0).UTC().Year()
+		}
+		c.addon = igdbAddonCategories[detail.Category]
+		for _, company := range detail.InvolvedCompanies {
+			if !company.Developer {
+				continue
+			}
+			if developer, err := GetIGDBCompanyCache(company.Company); err == nil {
+				c.Developers = append(c.Developers, developer)
+			}
+		}
+		scoreNames(c, queries)
+	}
+}
+
+// addSteamDetails sets the release year, developers and type of candidates
+func addSteamDetails(candidates []*matchCandidate) {
+	for _, c := range candidates {
+		detail, err := GetSteamAppDetailCache(c.PlatformID)
+		if err != nil {
+			continue
+		}
+		c.addon = steamAddonTypes[detail.Data.Type]
+		c.Developers = detail.Data.Developers
+		c.Year = ReleaseYear(detail.Data.ReleaseDate.Date)
+	}
+}
+
+// closeCandidates scores the names of candidates and keeps the closest ones,
+// the failed lookup is cached as negative if none is close
+func closeCandidates(source string, name string, queries []string, candidates []*matchCandidate, errs []error) ([]*matchCandidate, error) {
+	if len(candidates) == 0 {
+		if len(errs) == len(queries) {
+			return nil, storeNegative(source, name, errors.Join(errs...))
+		}
+		return nil, storeNegative(source, name, fmt.Errorf("%s: %w: %s", source, ErrIDNotFound, name))
+	}
+	for _, c := range candidates {
+		scoreNames(c, queries)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].nameScore > candidates[j].nameScore
+	})
+	n := 0
+	for n < len(candidates) && n < matchDetailCandidates && candidates[n].nameScore >= matchMinNameScore {
+		n++
+	}
+	if n == 0 {
+		return nil, storeNegative(source, name, fmt.Errorf("%s: %w: %s", source, ErrIDAmbiguous, name))
+	}
+	return candidates[:n], nil
+}
+
+// closestCandidates keeps the candidates with the closest names to queries, however close they are
+func closestCandidates(candidates []*matchCandidate, queries []string) []*matchCandidate {
+	for _, c := range candidates {
+		scoreNames(c, queries)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].nameScore > candidates[j].nameScore
+	})
+	return candidates[:min(len(candidates), matchDetailCandidates)]
+}
+
+// scoreNames sets the best similarity of the names of c to any of queries, the first name is the title
+func scoreNames(c *matchCandidate, queries []string) {
+	c.nameScore = 0
+	for i, candidateName := range c.names {
+		normalized := utils.NormalizeForIndex(candidateName)
+		if normalized == "" {
+			continue
+		}
+		for _, query := range queries {
+			score := utils.Similarity(utils.NormalizeForIndex(query), normalized)
+			if score > c.nameScore {
+				c.nameScore = score
+				c.query = query
+				c.alias = i > 0 && normalized != utils.NormalizeForIndex(c.Name)
+			}
+		}
+	}
+}
+
+// scoreCandidate sets the confidence of c from its name score and the other signals
+func scoreCandidate(c *matchCandidate, game *model.GameItem) {
+	c.Reasons = nil
+	confidence := c.nameScore
+	if c.alias {
+		confidence *= 0.95
+		c.Reasons = append(c.Reasons, fmt.Sprintf("alias similarity %.2f", c.nameScore))
+	} else {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("name similarity %.2f", c.nameScore))
+	}
+	if numberWords(c.query) != numberWords(c.Name) {
+		confidence -= 0.3
+		c.Reasons = append(c.Reasons, "sequel number differs")
+	}
+	raw := game.RawName
+	if raw == "" {
+		raw = game.Name
+	}
+	if itemYear := ReleaseYear(raw); itemYear != 0 && c.Year != 0 {
+		if diff := itemYear - c.Year; diff >= -1 && diff <= 1 {
+			confidence += 0.05
+			c.Reasons = append(c.Reasons, fmt.Sprintf("release year %d matches", c.Year))
+		} else {
+			confidence -= 0.2
+			c.Reasons = append(c.Reasons, fmt.Sprintf("release year %d differs from %d", c.Year, itemYear))
+		}
+	}
+	normalizedRaw := " " + utils.NormalizeForIndex(raw) + " "
+	for _, developer := range c.Developers {
+		normalized := utils.NormalizeForIndex(developer)
+		if len(normalized) >= 3 && strings.Contains(normalizedRaw, " "+normalized+" ") {
+			confidence += 0.05
+			c.Reasons = append(c.Reasons, fmt.Sprintf("developer %s in name", developer))
+			break
+		}
+	}
+	normalizedQuery := " " + utils.NormalizeForIndex(c.query) + " "
+	if c.addon && !strings.Contains(normalizedQuery, " dlc ") {
+		confidence -= 0.3
+		c.Reasons = append(c.Reasons, "not a base game")
+	}
+	normalizedName := " " + utils.NormalizeForIndex(c.Name) + " "
+	penalty := 0.0
+	for _, word := range editionWords {
+		if strings.Contains(normalizedName, " "+word+" ") && !strings.Contains(normalizedQuery, " "+word+" ") {
+			penalty += 0.05
+			c.Reasons = append(c.Reasons, fmt.Sprintf("%q not in name", word))
+		}
+	}
+	confidence -= min(penalty, 0.15)
+	c.Confidence = clampConfidence(confidence)
+}
+
+func clampConfidence(confidence float64) float64 {
+	return math.Round(max(0, min(1, confidence))*100) / 100
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
@@ -142,7 +143,7 @@ func (c *OnlineFixCrawler) CrawlByUrl(url string) (*model.GameItem, error) {
 		return nil, err
 	}
 	item.RawName = titleRegexRes[0][1]
-	item.Name = OnlineFixFormatter(item.RawName)
+	item.Name = normalize.OnlineFix.Normalize(item.RawName)
 	item.Url = url
 	item.Author = "OnlineFix"
 	item.Size = "0"
@@ -313,10 +314,3 @@ func (c *OnlineFixCrawler) login() error {
 	}
 	return nil
 }
-
-func OnlineFixFormatter(name string) string {
-	name = strings.Replace(name, "по сети", "", -1)
-	reg1 := regexp.MustCompile(`(?i)\(.*?\)`)
-	name = reg1.ReplaceAllString(name, "")
-	return strings.TrimSpace(name)
-}
@@ -0,0 +1,142 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nitezs/pcgamedb/cache"
+	"github.com/nitezs/pcgamedb/config"
+)
+
+// Reasons a name did not resolve to an ID
+const (
+	// ReasonNotFound means the search returned nothing
+	ReasonNotFound = "not_found"
+	// ReasonAmbiguous means the search returned games but none close enough to the name
+	ReasonAmbiguous = "ambiguous"
+	// ReasonAPIError means the lookup failed, e.g. the API was down or rate limited
+	ReasonAPIError = "api_error"
+)
+
+var (
+	ErrIDNotFound  = errors.New("ID not found")
+	ErrIDAmbiguous = errors.New("no close match")
+)
+
+// negativeNamespaces are the failed lookups of each source a name is resolved against
+var negativeNamespaces = map[string]cache.Namespace{
+	"igdb":  cache.NamespaceIGDBIDNegative,
+	"steam": cache.NamespaceSteamIDNegative,
+}
+
+// ResolveError is a failed name to ID lookup, Cached is set when it was served from the negative cache
+type ResolveError struct {
+	Source string
+	Name   string
+	Reason string
+	Cached bool
+	Err    error
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("%s ID of %q: %s: %v", e.Source, e.Name, e.Reason, e.Err)
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// NegativeEntry is a cached failed lookup
+type NegativeEntry struct {
+	Source   string    `json:"source"`
+	Name     string    `json:"name"`
+	Reason   string    `json:"reason"`
+	Error    string    `json:"error"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// resolveReason classifies a lookup error, if several names were tried
+// a failure of any of them that is not a clean miss counts as an API error
+func resolveReason(err error) string {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	reason := ReasonNotFound
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, ErrIDNotFound):
+		case errors.Is(err, ErrIDAmbiguous):
+			reason = ReasonAmbiguous
+		default:
+			return ReasonAPIError
+		}
+	}
+	return reason
+}
+
+// getIDCache resolves name with resolve, caching found IDs for cache.DefaultTTL
+// and failures for config.Config.Cache.Negative or NegativeError seconds
+func getIDCache(source string, namespace cache.Namespace, name string, resolve func(string) (int, error)) (int, error) {
+	if err := loadNegative(source, name); err != nil {
+		return 0, err
+	}
+	id, err := cache.GetOrLoad(namespace.Key(name), cache.DefaultTTL, func() (int, error) {
+		return resolve(name)
+	})
+	if err == nil {
+		return id, nil
+	}
+	return 0, storeNegative(source, name, err)
+}
+
+// loadNegative returns the cached failed lookup of name in source, or nil if there is none
+func loadNegative(source string, name string) error {
+	entry, ok := cache.Load[NegativeEntry](negativeNamespaces[source].Key(name))
+	if !ok {
+		return nil
+	}
+	return &ResolveError{Source: source, Name: name, Reason: entry.Reason, Cached: true, Err: errors.New(entry.Error)}
+}
+
+// storeNegative caches the failed lookup of name in source and returns it as a ResolveError
+func storeNegative(source string, name string, err error) error {
+	resolveErr := &ResolveError{Source: source, Name: name, Reason: resolveReason(err), Err: err}
+	ttl := config.Config.Cache.Negative
+	if resolveErr.Reason == ReasonAPIError {
+		ttl = config.Config.Cache.NegativeError
+	}
+	if ttl > 0 {
+		now := time.Now()
+		cache.Store(negativeNamespaces[source].Key(name), NegativeEntry{
+			Source:   source,
+			Name:     name,
+			Reason:   resolveErr.Reason,
+			Error:    err.Error(),
+			CachedAt: now,
+		}, time.Duration(ttl)*time.Second, now)
+	}
+	return resolveErr
+}
+
+// GetNegativeEntries returns the cached failed lookups of name
+func GetNegativeEntries(name string) []NegativeEntry {
+	res := []NegativeEntry{}
+	for _, source := range []string{"igdb", "steam"} {
+		if entry, ok := cache.Load[NegativeEntry](negativeNamespaces[source].Key(name)); ok {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
+
+// ClearNegativeEntries drops the cached failed lookups of name, so that it is looked up again
+func ClearNegativeEntries(name string) error {
+	for _, namespace := range negativeNamespaces {
+		if err := cache.Delete(namespace.Key(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
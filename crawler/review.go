@@ -0,0 +1,133 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// reviewReason returns why a failed match goes to the review queue,
+// or an empty string if it should be retried instead
+func reviewReason(err error) string {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	reason := model.ReviewReasonNotFound
+	for _, err := range errs {
+		var resolveErr *ResolveError
+		if !errors.As(err, &resolveErr) {
+			return ""
+		}
+		switch resolveErr.Reason {
+		case ReasonAPIError:
+			return ""
+		case ReasonAmbiguous:
+			reason = model.ReviewReasonAmbiguous
+		}
+	}
+	return reason
+}
+
+func queueReview(game *model.GameItem, reason string, err error, candidates []model.MatchCandidate) error {
+	review := &model.MatchReview{
+		GameID:     game.ID,
+		Name:       game.Name,
+		RawName:    game.RawName,
+		Author:     game.Author,
+		Reason:     reason,
+		Candidates: candidates,
+	}
+	if err != nil {
+		review.Error = err.Error()
+	}
+	if review.Candidates == nil {
+		review.Candidates = []model.MatchCandidate{}
+	}
+	return db.QueueMatchReview(review)
+}
+
+// AcceptMatchReview links the game item of review to chosen, which is one of its candidates
+// or another game picked by the reviewer, and saves the game info.
+// The review is claimed first, so that concurrent reviewers cannot both link the item,
+// and is put back in the queue if the link fails.
+func AcceptMatchReview(review *model.MatchReview, chosen model.MatchCandidate, reviewer string) (*model.GameInfo, error) {
+	if review.Status != model.MatchReviewPending {
+		return nil, fmt.Errorf("review is already %s", review.Status)
+	}
+	if err := db.SetMatchReviewStatus(review.ID, model.MatchReviewAccepted, reviewer); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("review is no longer pending")
+		}
+		return nil, err
+	}
+	info, game, err := linkMatchReview(review, chosen, reviewer)
+	if err != nil {
+		if err := db.ReopenMatchReview(review.ID); err != nil {
+			log.Logger.Warn("Failed to reopen review", zap.String("name", review.Name), zap.Error(err))
+		}
+		return nil, err
+	}
+	review.Status = model.MatchReviewAccepted
+	review.ReviewedBy = reviewer
+	if err := LearnAlias(game, chosen.Platform, chosen.PlatformID, AliasSourceReview); err != nil {
+		log.Logger.Warn("Failed to learn alias", zap.String("name", game.Name), zap.Error(err))
+	}
+	return info, nil
+}
+
+// linkMatchReview links the game item of a claimed review to chosen and saves the game info
+func linkMatchReview(review *model.MatchReview, chosen model.MatchCandidate, reviewer string) (*model.GameInfo, *model.GameItem, error) {
+	game, err := db.GetGameItemByID(review.GameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	index := slices.IndexFunc(review.Candidates, func(c model.MatchCandidate) bool {
+		return c.Platform == chosen.Platform && c.PlatformID == chosen.PlatformID
+	})
+	if index != -1 {
+		chosen = review.Candidates[index]
+	} else {
+		// picked by the reviewer
+		chosen.Confidence = 1
+	}
+	chosen.Reasons = append(slices.Clone(chosen.Reasons), "accepted by "+reviewer)
+	var runnerUps []model.MatchCandidate
+	for i, c := range review.Candidates {
+		if i != index {
+			runnerUps = append(runnerUps, c)
+		}
+	}
+	info, err := LinkGameItem(game, chosen, runnerUps)
+	if err != nil {
+		return nil, nil, err
+	}
+	if index == -1 {
+		info.Matches[len(info.Matches)-1].Chosen.Name = info.Name
+	}
+	if err := db.SaveGameInfo(info); err != nil {
+		return nil, nil, err
+	}
+	return info, game, nil
+}
+
+// RejectMatchReview records that none of the candidates of review is right,
+// the game item stays unorganized and is not matched automatically again
+func RejectMatchReview(review *model.MatchReview, reviewer string) error {
+	if review.Status != model.MatchReviewPending {
+		return fmt.Errorf("review is already %s", review.Status)
+	}
+	if err := db.SetMatchReviewStatus(review.ID, model.MatchReviewRejected, reviewer); err != nil {
+		return err
+	}
+	review.Status = model.MatchReviewRejected
+	review.ReviewedBy = reviewer
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -14,10 +15,17 @@ import (
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 )
 
-func _GetSteamID(name string) (int, error) {
+type SteamSearchResult struct {
+	ID   int
+	Name string
+}
+
+// SearchSteam returns the games the Steam store search finds for name
+func SearchSteam(name string) ([]SteamSearchResult, error) {
 	baseURL, _ := url.Parse(constant.SteamSearchURL)
 	params := url.Values{}
 	params.Add("term", name)
@@ -27,78 +35,93 @@ func _GetSteamID(name string) (int, error) {
 		Url: baseURL.String(),
 	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	idRegex := regexp.MustCompile(`data-ds-appid="(.*?)"`)
 	nameRegex := regexp.MustCompile(`<span class="title">(.*?)</span>`)
 	idRegexRes := idRegex.FindAllStringSubmatch(string(resp.Data), -1)
 	nameRegexRes := nameRegex.FindAllStringSubmatch(string(resp.Data), -1)
 
-	if len(idRegexRes) == 0 {
-		return 0, fmt.Errorf("Steam ID not found: %s", name)
-	}
-
-	maxSim := 0.0
-	maxSimID := 0
+	var res []SteamSearchResult
 	for i, id := range idRegexRes {
+		if i >= len(nameRegexRes) {
+			break
+		}
 		idStr := id[1]
-		nameStr := nameRegexRes[i][1]
 		if index := strings.Index(idStr, ","); index != -1 {
 			idStr = idStr[:index]
 		}
-		if strings.EqualFold(strings.TrimSpace(nameStr), strings.TrimSpace(name)) {
-			return strconv.Atoi(idStr)
+		steamID, err := strconv.Atoi(idStr)
+		if err != nil {
+			continue
+		}
+		res = append(res, SteamSearchResult{ID: steamID, Name: html.UnescapeString(nameRegexRes[i][1])})
+	}
+	return res, nil
+}
+
+// SearchSteamCache is SearchSteam with the results cached, failed searches are not cached
+func SearchSteamCache(name string) ([]SteamSearchResult, error) {
+	key := cache.NamespaceSteamSearch.Key(name)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() ([]SteamSearchResult, error) {
+		return SearchSteam(name)
+	})
+}
+
+func _GetSteamID(name string) (int, error) {
+	results, err := SearchSteam(name)
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, fmt.Errorf("Steam: %w: %s", ErrIDNotFound, name)
+	}
+
+	maxSim := 0.0
+	maxSimID := 0
+	for _, result := range results {
+		if strings.EqualFold(strings.TrimSpace(result.Name), strings.TrimSpace(name)) {
+			return result.ID, nil
 		} else {
-			sim := utils.Similarity(nameStr, name)
+			sim := utils.Similarity(result.Name, name)
 			if sim >= 0.8 && sim > maxSim {
 				maxSim = sim
-				maxSimID, _ = strconv.Atoi(idStr)
+				maxSimID = result.ID
 			}
 		}
 	}
 	if maxSimID != 0 {
 		return maxSimID, nil
 	}
-	return 0, fmt.Errorf("Steam ID not found: %s", name)
+	return 0, fmt.Errorf("Steam: %w: %s", ErrIDAmbiguous, name)
 }
 
 func GetSteamID(name string) (int, error) {
 	name1 := name
-	name2 := FormatName(name)
+	name2 := normalize.Generic.Normalize(name)
 	names := []string{name1}
 	if name1 != name2 {
 		names = append(names, name2)
 	}
+	// the local app list answers most names without a request
+	for _, n := range names {
+		if id, err := GetSteamIDFromApps(n); err == nil {
+			return id, nil
+		}
+	}
+	errs := make([]error, 0, len(names))
 	for _, n := range names {
 		id, err := _GetSteamID(n)
 		if err == nil {
 			return id, nil
 		}
+		errs = append(errs, err)
 	}
-	return 0, errors.New("Steam ID not found")
+	return 0, errors.Join(errs...)
 }
 
 func GetSteamIDCache(name string) (int, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("steam_id:%s", name)
-		val, exist := cache.Get(key)
-		if exist {
-			id, err := strconv.Atoi(val)
-			if err != nil {
-				return 0, err
-			}
-			return id, nil
-		} else {
-			id, err := GetSteamID(name)
-			if err != nil {
-				return 0, err
-			}
-			_ = cache.Add(key, id)
-			return id, nil
-		}
-	} else {
-		return GetSteamID(name)
-	}
+	return getIDCache("steam", cache.NamespaceSteamID, name, GetSteamID)
 }
 
 func GetSteamAppDetail(id int) (*model.SteamAppDetail, error) {
@@ -130,30 +153,10 @@ func GetSteamAppDetail(id int) (*model.SteamAppDetail, error) {
 }
 
 func GetSteamAppDetailCache(id int) (*model.SteamAppDetail, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("steam_game:%d", id)
-		val, exist := cache.Get(key)
-		if exist {
-			var detail model.SteamAppDetail
-			if err := json.Unmarshal([]byte(val), &detail); err != nil {
-				return nil, err
-			}
-			return &detail, nil
-		} else {
-			data, err := GetSteamAppDetail(id)
-			if err != nil {
-				return nil, err
-			}
-			dataBytes, err := json.Marshal(data)
-			if err != nil {
-				return nil, err
-			}
-			_ = cache.Add(key, dataBytes)
-			return data, nil
-		}
-	} else {
+	key := cache.NamespaceSteamGame.Key(id)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() (*model.SteamAppDetail, error) {
 		return GetSteamAppDetail(id)
-	}
+	})
 }
 
 func GenerateSteamGameInfo(id int) (*model.GameInfo, error) {
@@ -184,7 +187,7 @@ func OrganizeGameItemWithSteam(id int, game *model.GameItem) (*model.GameInfo, e
 			return nil, err
 		}
 	}
-	d, err := db.GetGameInfoByPlatformID("steam", id)
+	d, err := db.GetGameInfoByPlatformID("steam", id, nil)
 	if err == nil {
 		d.GameIDs = append(d.GameIDs, game.ID)
 		d.GameIDs = utils.Unique(d.GameIDs)
@@ -249,25 +252,8 @@ func GetSteamIDByIGDBID(IGDBID int) (int, error) {
 }
 
 func GetSteamIDByIGDBIDCache(IGDBID int) (int, error) {
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("steam_game:%d", IGDBID)
-		val, exist := cache.Get(key)
-		if exist {
-			id, err := strconv.Atoi(val)
-			if err != nil {
-				return 0, err
-			}
-			return id, nil
-		} else {
-			id, err := GetSteamIDByIGDBID(IGDBID)
-			if err != nil {
-				return 0, err
-			}
-			dataBytes := strconv.Itoa(id)
-			_ = cache.Add(key, dataBytes)
-			return id, nil
-		}
-	} else {
+	key := cache.NamespaceSteamIDByIGDBID.Key(IGDBID)
+	return cache.GetOrLoad(key, cache.DefaultTTL, func() (int, error) {
 		return GetSteamIDByIGDBID(IGDBID)
-	}
+	})
 }
@@ -2,23 +2,23 @@ package crawler
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/nitezs/pcgamedb/cache"
-	"github.com/nitezs/pcgamedb/config"
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func GetSteam250(url string) ([]*model.GameInfo, error) {
+// GetSteam250 returns the IDs of the game infos in a Steam250 ranking, in ranking order
+func GetSteam250(url string) ([]primitive.ObjectID, error) {
 	resp, err := utils.Fetch(utils.FetchConfig{
 		Url: url,
 	})
@@ -43,78 +43,78 @@ func GetSteam250(url string) ([]*model.GameInfo, error) {
 		rank = append(rank, item)
 		steamIDs = append(steamIDs, item.SteamID)
 	})
-	var res []*model.GameInfo
-	count := 0
+	var res []primitive.ObjectID
 	for _, steamID := range steamIDs {
-		info, err := db.GetGameInfoByPlatformID("steam", steamID)
+		info, err := db.GetGameInfoByPlatformID("steam", steamID, []string{"id"})
 		if err == nil {
-			res = append(res, info)
-			count++
-			continue
+			res = append(res, info.ID)
 		}
 	}
 	return res, nil
 }
 
-func GetSteam250Top250() ([]*model.GameInfo, error) {
+func GetSteam250Top250() ([]primitive.ObjectID, error) {
 	return GetSteam250(constant.Steam250Top250URL)
 }
 
-func GetSteam250Top250Cache() ([]*model.GameInfo, error) {
+func GetSteam250Top250Cache() ([]primitive.ObjectID, error) {
 	return GetSteam250Cache("top250", GetSteam250Top250)
 }
 
-func GetSteam250BestOfTheYear() ([]*model.GameInfo, error) {
+func GetSteam250BestOfTheYear() ([]primitive.ObjectID, error) {
 	return GetSteam250(fmt.Sprintf(constant.Steam250BestOfTheYearURL, time.Now().UTC().Year()))
 }
 
-func GetSteam250BestOfTheYearCache() ([]*model.GameInfo, error) {
+func GetSteam250BestOfTheYearCache() ([]primitive.ObjectID, error) {
 	return GetSteam250Cache(fmt.Sprintf("bestoftheyear:%v", time.Now().UTC().Year()), GetSteam250BestOfTheYear)
 }
 
-func GetSteam250WeekTop50() ([]*model.GameInfo, error) {
+func GetSteam250WeekTop50() ([]primitive.ObjectID, error) {
 	return GetSteam250(constant.Steam250WeekTop50URL)
 }
 
-func GetSteam250WeekTop50Cache() ([]*model.GameInfo, error) {
+func GetSteam250WeekTop50Cache() ([]primitive.ObjectID, error) {
 	return GetSteam250Cache("weektop50", GetSteam250WeekTop50)
 }
 
-func GetSteam250MostPlayed() ([]*model.GameInfo, error) {
+func GetSteam250MostPlayed() ([]primitive.ObjectID, error) {
 	return GetSteam250(constant.Steam250MostPlayedURL)
 }
 
-func GetSteam250MostPlayedCache() ([]*model.GameInfo, error) {
+func GetSteam250MostPlayedCache() ([]primitive.ObjectID, error) {
 	return GetSteam250Cache("mostplayed", GetSteam250MostPlayed)
 }
 
-func GetSteam250Cache(k string, f func() ([]*model.GameInfo, error)) ([]*model.GameInfo, error) {
-	if config.Config.RedisAvaliable {
-		key := k
-		val, exist := cache.Get(key)
-		if exist {
-			var res []*model.GameInfo
-			err := json.Unmarshal([]byte(val), &res)
-			if err != nil {
-				return nil, err
-			}
-			return res, nil
-		} else {
-			data, err := f()
-			if err != nil {
-				return nil, err
-			}
-			dataBytes, err := json.Marshal(data)
-			if err != nil {
-				return data, nil
-			}
-			err = cache.AddWithExpire(key, dataBytes, 12*time.Hour)
-			if err != nil {
-				return data, nil
-			}
-			return data, nil
-		}
-	} else {
-		return f()
+// Steam250RankingTypes are the ranking types served by the API
+var Steam250RankingTypes = []string{"top", "week-top", "best-of-the-year", "most-played"}
+
+// GetSteam250RankingCache returns the IDs of the game infos in a ranking, in ranking order.
+// Only the IDs are cached, so that the game infos can be loaded with the fields a request needs.
+func GetSteam250RankingCache(rankingType string) ([]primitive.ObjectID, error) {
+	switch rankingType {
+	case "top":
+		return GetSteam250Top250Cache()
+	case "week-top":
+		return GetSteam250WeekTop50Cache()
+	case "best-of-the-year":
+		return GetSteam250BestOfTheYearCache()
+	case "most-played":
+		return GetSteam250MostPlayedCache()
+	default:
+		return nil, fmt.Errorf("invalid ranking type: %s", rankingType)
 	}
 }
+
+func GetSteam250Cache(k string, f func() ([]primitive.ObjectID, error)) ([]primitive.ObjectID, error) {
+	return cache.GetOrLoadTagged(cache.NamespaceSteam250.Key(k), 12*time.Hour, func() ([]primitive.ObjectID, []string, error) {
+		data, err := f()
+		if err != nil {
+			return nil, nil, err
+		}
+		tags := make([]string, 0, len(data))
+		for _, id := range data {
+			tags = append(tags, cache.DocTag(id.Hex()))
+		}
+		return data, tags, nil
+	})
+}
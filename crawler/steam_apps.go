@@ -0,0 +1,119 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/constant"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
+)
+
+const (
+	// steamAppMinScore is the utils.Similarity a fuzzy match of the local Steam app list needs
+	steamAppMinScore = 0.93
+	// steamAppMinLead is how much better than the runner-up a fuzzy match has to be
+	steamAppMinLead = 0.02
+)
+
+type steamAppList struct {
+	AppList struct {
+		Apps []*model.SteamApp `json:"apps"`
+	} `json:"applist"`
+}
+
+// ImportSteamApps replaces the local Steam app list with the GetAppList JSON read from path,
+// or fetched from constant.SteamAllAppsURL if path is empty, and returns the number of apps
+func ImportSteamApps(path string) (int, error) {
+	var data []byte
+	if path == "" {
+		resp, err := utils.Fetch(utils.FetchConfig{
+			Url: constant.SteamAllAppsURL,
+		})
+		if err != nil {
+			return 0, err
+		}
+		data = resp.Data
+	} else {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+	}
+	var list steamAppList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal Steam app list: %w", err)
+	}
+	apps := make([]*model.SteamApp, 0, len(list.AppList.Apps))
+	for _, app := range list.AppList.Apps {
+		if app.AppID != 0 && strings.TrimSpace(app.Name) != "" {
+			apps = append(apps, app)
+		}
+	}
+	// an empty list is a broken download rather than Steam having no apps
+	if len(apps) == 0 {
+		return 0, fmt.Errorf("Steam app list is empty")
+	}
+	start := time.Now()
+	if err := db.SaveSteamApps(apps, start); err != nil {
+		return 0, err
+	}
+	if _, err := db.DeleteSteamAppsUpdatedBefore(start); err != nil {
+		return 0, err
+	}
+	return len(apps), db.ReloadSteamAppIndex()
+}
+
+// GetSteamIDFromApps resolves name against the local Steam app list,
+// by normalized name first and then by a close fuzzy match agreeing on sequel numbers
+func GetSteamIDFromApps(name string) (int, error) {
+	exact, err := db.FindSteamAppsByName(name)
+	if err != nil {
+		return 0, err
+	}
+	if len(exact) == 1 {
+		return exact[0].AppID, nil
+	}
+	if len(exact) > 1 {
+		return 0, fmt.Errorf("Steam app list: %w: %s", ErrIDAmbiguous, name)
+	}
+	matches, err := db.FuzzySearchSteamApps(name, 10, steamAppMinScore)
+	if err != nil {
+		return 0, err
+	}
+	numbers := numberWords(name)
+	candidates := matches[:0]
+	for _, match := range matches {
+		if numberWords(match.Name) == numbers {
+			candidates = append(candidates, match)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, fmt.Errorf("Steam app list: %w: %s", ErrIDNotFound, name)
+	}
+	if len(candidates) > 1 && candidates[0].Score-candidates[1].Score < steamAppMinLead {
+		return 0, fmt.Errorf("Steam app list: %w: %s", ErrIDAmbiguous, name)
+	}
+	return candidates[0].AppID, nil
+}
+
+var romanNumerals = map[string]bool{
+	"ii": true, "iii": true, "iv": true, "v": true, "vi": true, "vii": true, "viii": true, "ix": true, "x": true,
+	"xi": true, "xii": true, "xiii": true,
+}
+
+// numberWords returns the numbers in name, which tell sequels apart even when the names are close
+func numberWords(name string) string {
+	var numbers []string
+	for _, word := range strings.Fields(utils.NormalizeForIndex(name)) {
+		if romanNumerals[word] || strings.Trim(word, "0123456789") == "" {
+			numbers = append(numbers, word)
+		}
+	}
+	return strings.Join(numbers, " ")
+}
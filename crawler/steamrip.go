@@ -10,6 +10,7 @@ import (
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
@@ -46,7 +47,7 @@ func (c *SteamRIPCrawler) CrawlByUrl(url string) (*model.GameItem, error) {
 		return nil, err
 	}
 	item.RawName = strings.TrimSpace(doc.Find(".entry-title").First().Text())
-	item.Name = SteamRIPFormatter(item.RawName)
+	item.Name = normalize.SteamRIP.Normalize(item.RawName)
 	item.Url = url
 	item.Author = "SteamRIP"
 	sizeRegex := regexp.MustCompile(`(?i)<li><strong>Game Size:\s?</strong>(.*?)</li>`)
@@ -142,10 +143,3 @@ func (c *SteamRIPCrawler) Crawl(num int) ([]*model.GameItem, error) {
 func (c *SteamRIPCrawler) CrawlAll() ([]*model.GameItem, error) {
 	return c.Crawl(-1)
 }
-
-func SteamRIPFormatter(name string) string {
-	name = regexp.MustCompile(`\([^\)]+\)`).ReplaceAllString(name, "")
-	name = strings.Replace(name, "Free Download", "", -1)
-	name = strings.TrimSpace(name)
-	return name
-}
@@ -4,13 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"regexp"
 	"strconv"
-	"strings"
 
 	"github.com/nitezs/pcgamedb/constant"
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/normalize"
 	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/PuerkitoBio/goquery"
@@ -103,7 +102,7 @@ func (c *XatabCrawler) CrawlByUrl(url string) (*model.GameItem, error) {
 	}
 	item.Url = url
 	item.RawName = doc.Find(".inner-entry__title").First().Text()
-	item.Name = XatabFormatter(item.RawName)
+	item.Name = normalize.Xatab.Normalize(item.RawName)
 	item.Author = "Xatab"
 	item.UpdateFlag = item.RawName
 	downloadURL := doc.Find("#download>a").First().AttrOr("href", "")
@@ -179,45 +178,3 @@ func (c *XatabCrawler) GetTotalPageNum() (int, error) {
 	}
 	return totalPageNum, nil
 }
-
-var xatabRegexps = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\sPC$`),
-}
-
-func XatabFormatter(name string) string {
-	reg1 := regexp.MustCompile(`(?i)v(er)?\s?(\.)?\d+(\.\d+)*`)
-	if index := reg1.FindIndex([]byte(name)); index != nil {
-		name = name[:index[0]]
-	}
-	if index := strings.Index(name, "["); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "("); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "{"); index != -1 {
-		name = name[:index]
-	}
-	if index := strings.Index(name, "+"); index != -1 {
-		name = name[:index]
-	}
-	name = strings.TrimSpace(name)
-	for _, re := range xatabRegexps {
-		name = re.ReplaceAllString(name, "")
-	}
-
-	if index := strings.Index(name, "/"); index != -1 {
-		names := strings.Split(name, "/")
-		longestLength := 0
-		longestName := ""
-		for _, n := range names {
-			if !utils.ContainsRussian(n) && len(n) > longestLength {
-				longestLength = len(n)
-				longestName = n
-			}
-		}
-		name = longestName
-	}
-
-	return strings.TrimSpace(name)
-}
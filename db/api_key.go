@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	apiKeyPrefix       = "pgdb_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+	apiKeyLength       = len(apiKeyPrefix) + 48
+)
+
+// IsAPIKey reports whether key has the format of the keys made by CreateAPIKey,
+// so that other strings are rejected without a query
+func IsAPIKey(key string) bool {
+	if len(key) != apiKeyLength || !strings.HasPrefix(key, apiKeyPrefix) {
+		return false
+	}
+	_, err := hex.DecodeString(key[len(apiKeyPrefix):])
+	return err == nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+var ErrReservedOwner = errors.New("owner " + model.AdminOwner + " is reserved for the server secret key")
+
+// CreateAPIKey stores a new key and returns it, the key itself is not stored and cannot be shown again
+func CreateAPIKey(apiKey *model.APIKey) (string, error) {
+	if strings.EqualFold(strings.TrimSpace(apiKey.Owner), model.AdminOwner) {
+		return "", ErrReservedOwner
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(b)
+	apiKey.ID = primitive.NewObjectID()
+	apiKey.Prefix = key[:apiKeyPrefixLength]
+	apiKey.Hash = hashAPIKey(key)
+	apiKey.CreatedAt = time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := options.Update().SetUpsert(true)
+	_, err := APIKeyCollection.UpdateOne(ctx, bson.M{"_id": apiKey.ID}, bson.M{"$set": apiKey}, opts)
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// GetAPIKeyByKey returns the stored key matching the key presented by a client
+func GetAPIKeyByKey(key string) (*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var apiKey model.APIKey
+	err := APIKeyCollection.FindOne(ctx, bson.M{"hash": hashAPIKey(key)}).Decode(&apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func GetAllAPIKeys() ([]*model.APIKey, error) {
+	var res []*model.APIKey
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "owner", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := APIKeyCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RevokeAPIKey revokes a key by ID or by prefix
+func RevokeAPIKey(idOrPrefix string) (*model.APIKey, error) {
+	filter := bson.M{"prefix": idOrPrefix}
+	if id, err := primitive.ObjectIDFromHex(idOrPrefix); err == nil {
+		filter = bson.M{"_id": id}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	count, err := APIKeyCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if count > 1 {
+		return nil, errors.New("prefix matches several keys, revoke by ID")
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var apiKey model.APIKey
+	err = APIKeyCollection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}}, opts).Decode(&apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func TouchAPIKey(id primitive.ObjectID, usedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := APIKeyCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	return err
+}
@@ -76,3 +76,21 @@ func (c *CustomCollection) CountDocuments(ctx context.Context, filter interface{
 	}
 	return c.coll.CountDocuments(ctx, filter, opts...)
 }
+
+func (c *CustomCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{},
+	opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	CheckConnect()
+	if c.coll == nil {
+		c.coll = mongoDB.Database(config.Config.Database.Database).Collection(c.collName)
+	}
+	return c.coll.FindOneAndUpdate(ctx, filter, update, opts...)
+}
+
+func (c *CustomCollection) BulkWrite(ctx context.Context, models []mongo.WriteModel,
+	opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	CheckConnect()
+	if c.coll == nil {
+		c.coll = mongoDB.Database(config.Config.Database.Database).Collection(c.collName)
+	}
+	return c.coll.BulkWrite(ctx, models, opts...)
+}
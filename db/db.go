@@ -16,8 +16,16 @@ import (
 )
 
 const (
-	gameDownloadCollectionName = "games"
-	gameInfoCollectionName     = "game_infos"
+	gameDownloadCollectionName    = "games"
+	gameInfoCollectionName        = "game_infos"
+	webhookCollectionName         = "webhooks"
+	webhookDeliveryCollectionName = "webhook_deliveries"
+	notifierCollectionName        = "notifiers"
+	watchlistCollectionName       = "watchlist"
+	apiKeyCollectionName          = "api_keys"
+	steamAppCollectionName        = "steam_apps"
+	matchReviewCollectionName     = "match_reviews"
+	nameAliasCollectionName       = "name_aliases"
 )
 
 var (
@@ -29,6 +37,30 @@ var (
 	GameInfoCollection = &CustomCollection{
 		collName: gameInfoCollectionName,
 	}
+	WebhookCollection = &CustomCollection{
+		collName: webhookCollectionName,
+	}
+	WebhookDeliveryCollection = &CustomCollection{
+		collName: webhookDeliveryCollectionName,
+	}
+	NotifierCollection = &CustomCollection{
+		collName: notifierCollectionName,
+	}
+	WatchlistCollection = &CustomCollection{
+		collName: watchlistCollectionName,
+	}
+	APIKeyCollection = &CustomCollection{
+		collName: apiKeyCollectionName,
+	}
+	SteamAppCollection = &CustomCollection{
+		collName: steamAppCollectionName,
+	}
+	MatchReviewCollection = &CustomCollection{
+		collName: matchReviewCollectionName,
+	}
+	NameAliasCollection = &CustomCollection{
+		collName: nameAliasCollectionName,
+	}
 )
 
 func connect() {
@@ -71,6 +103,51 @@ func connect() {
 			{Key: "author", Value: 1},
 		},
 	}
+	updatedAtIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "updated_at", Value: -1},
+		},
+	}
+	webhookDeliveryIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "webhook_id", Value: 1},
+			{Key: "_id", Value: -1},
+		},
+	}
+	dueDeliveryIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "next_attempt_at", Value: 1},
+		},
+	}
+	ownerIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "owner", Value: 1},
+		},
+	}
+	apiKeyHashIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "hash", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	steamAppUpdatedAtIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "updated_at", Value: 1},
+		},
+	}
+	matchReviewGameIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "game_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	matchReviewStatusIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "_id", Value: -1},
+		},
+	}
 	gamesIndex := mongo.IndexModel{
 		Keys: bson.D{
 			{Key: "games", Value: 1},
@@ -89,6 +166,10 @@ func connect() {
 	if err != nil {
 		log.Logger.Error("Failed to create index", zap.Error(err))
 	}
+	_, err = gameDownloadCollection.Indexes().CreateOne(ctx, updatedAtIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
 	_, err = gameInfoCollection.Indexes().CreateOne(ctx, gamesIndex)
 	if err != nil {
 		log.Logger.Error("Failed to create index", zap.Error(err))
@@ -97,6 +178,31 @@ func connect() {
 	if err != nil {
 		log.Logger.Error("Failed to create index", zap.Error(err))
 	}
+	webhookDeliveryCollection := mongoDB.Database(config.Config.Database.Database).Collection(webhookDeliveryCollectionName)
+	_, err = webhookDeliveryCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{webhookDeliveryIndex, dueDeliveryIndex})
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	watchlistCollection := mongoDB.Database(config.Config.Database.Database).Collection(watchlistCollectionName)
+	_, err = watchlistCollection.Indexes().CreateOne(ctx, ownerIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	apiKeyCollection := mongoDB.Database(config.Config.Database.Database).Collection(apiKeyCollectionName)
+	_, err = apiKeyCollection.Indexes().CreateOne(ctx, apiKeyHashIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	steamAppCollection := mongoDB.Database(config.Config.Database.Database).Collection(steamAppCollectionName)
+	_, err = steamAppCollection.Indexes().CreateOne(ctx, steamAppUpdatedAtIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	matchReviewCollection := mongoDB.Database(config.Config.Database.Database).Collection(matchReviewCollectionName)
+	_, err = matchReviewCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{matchReviewGameIndex, matchReviewStatusIndex})
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
 }
 
 func CheckConnect() {
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetLatestGameItems returns the most recently crawled or updated game items,
+// limited to one author if author is not empty
+func GetLatestGameItems(author string, limit int) ([]*model.GameItem, error) {
+	var res []*model.GameItem
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.M{}
+	if author != "" {
+		filter["author"] = exactRegex(author)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := GameItemCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetGameInfosByGameItemIDs maps game item IDs to the game info they are organized into,
+// items that are not organized yet are missing from the result
+func GetGameInfosByGameItemIDs(ids []primitive.ObjectID) (map[primitive.ObjectID]*model.GameInfo, error) {
+	res := make(map[primitive.ObjectID]*model.GameInfo)
+	if len(ids) == 0 {
+		return res, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := GameInfoCollection.Find(ctx, bson.M{"games": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var infos []*model.GameInfo
+	if err = cursor.All(ctx, &infos); err != nil {
+		return nil, err
+	}
+	wanted := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for _, info := range infos {
+		for _, id := range info.GameIDs {
+			if wanted[id] {
+				res[id] = info
+			}
+		}
+	}
+	return res, nil
+}
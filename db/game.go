@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/nitezs/pcgamedb/cache"
-	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/events"
 	"github.com/nitezs/pcgamedb/model"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -44,31 +45,121 @@ func GetGameItemsByAuthor(regex string) ([]*model.GameItem, error) {
 	return res, err
 }
 
-func GetGameItemsByAuthorPagination(regex string, page int, pageSize int) ([]*model.GameItem, int, error) {
+func GetGameItemsByAuthorPage(regex string, page *Page) ([]*model.GameItem, string, int64, error) {
 	var res []*model.GameItem
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	filter := bson.D{{Key: "author", Value: primitive.Regex{Pattern: regex, Options: "i"}}}
-	opts := options.Find()
-	opts.SetSkip(int64((page - 1) * pageSize))
-	opts.SetLimit(int64(pageSize))
+	filter := bson.M{"author": primitive.Regex{Pattern: regex, Options: "i"}}
 	totalCount, err := GameItemCollection.CountDocuments(ctx, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, "", 0, err
 	}
-	totalPage := (totalCount + int64(pageSize) - 1) / int64(pageSize)
+	if after := page.afterID(); after != nil {
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "_id", Value: 1}})
+	opts.SetSkip(page.skip())
+	opts.SetLimit(int64(page.Limit + 1))
 	cursor, err := GameItemCollection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, "", 0, err
 	}
 	defer cursor.Close(ctx)
 	if cursor.Err() != nil {
-		return nil, 0, cursor.Err()
+		return nil, "", 0, cursor.Err()
 	}
 	if err = cursor.All(ctx, &res); err != nil {
-		return nil, 0, err
+		return nil, "", 0, err
+	}
+	res, more := trimPage(res, page.Limit)
+	next := ""
+	if more {
+		id := res[len(res)-1].ID
+		next = (&Cursor{ID: &id}).String()
+	}
+	return res, next, totalCount, nil
+}
+
+// AuthorGameItems is a page of the game items of one author
+type AuthorGameItems struct {
+	Items []*model.GameItem
+	Next  string
+	Total int64
+}
+
+// GetGameItemsByAuthorsPage returns the same page of the game items of every author with two queries,
+// keyed by the lower case author name. Authors are matched exactly, ignoring case.
+func GetGameItemsByAuthorsPage(authors []string, page *Page) (map[string]*AuthorGameItems, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	names := make(bson.A, 0, len(authors))
+	for _, author := range authors {
+		names = append(names, exactRegex(author))
+	}
+	filter := bson.M{"author": bson.M{"$in": names}}
+	byAuthor := bson.M{"$toLower": "$author"}
+
+	cursor, err := GameItemCollection.Aggregate(ctx, mongo.Pipeline{
+		bson.D{{Key: "$match", Value: filter}},
+		bson.D{{Key: "$group", Value: bson.M{"_id": byAuthor, "total": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var totals []struct {
+		Author string `bson:"_id"`
+		Total  int64  `bson:"total"`
+	}
+	if err = cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+	res := make(map[string]*AuthorGameItems, len(authors))
+	for _, author := range authors {
+		res[strings.ToLower(strings.TrimSpace(author))] = &AuthorGameItems{Items: []*model.GameItem{}}
+	}
+	for _, total := range totals {
+		if r, ok := res[total.Author]; ok {
+			r.Total = total.Total
+		}
+	}
+
+	if after := page.afterID(); after != nil {
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+	skip := page.skip()
+	cursor, err = GameItemCollection.Aggregate(ctx, mongo.Pipeline{
+		bson.D{{Key: "$match", Value: filter}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":   byAuthor,
+			"items": bson.M{"$firstN": bson.M{"input": "$$ROOT", "n": skip + int64(page.Limit) + 1}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Author string            `bson:"_id"`
+		Items  []*model.GameItem `bson:"items"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		r, ok := res[group.Author]
+		if !ok {
+			continue
+		}
+		items := group.Items[min(int(skip), len(group.Items)):]
+		items, more := trimPage(items, page.Limit)
+		r.Items = items
+		if more {
+			id := items[len(items)-1].ID
+			r.Next = (&Cursor{ID: &id}).String()
+		}
 	}
-	return res, int(totalPage), err
+	return res, nil
 }
 
 func IsGameCrawled(flag string, author string) bool {
@@ -106,6 +197,24 @@ func IsGameCrawledByURL(url string) bool {
 	return true
 }
 
+// invalidateGameItems drops the cached responses that contain the items or list game items
+func invalidateGameItems(ids ...primitive.ObjectID) {
+	tags := []string{cache.TagGameItems}
+	for _, id := range ids {
+		tags = append(tags, cache.DocTag(id.Hex()))
+	}
+	cache.InvalidateTags(tags...)
+}
+
+// invalidateGameInfos drops the cached responses that contain the infos or list game infos
+func invalidateGameInfos(ids ...primitive.ObjectID) {
+	tags := []string{cache.TagGameInfos}
+	for _, id := range ids {
+		tags = append(tags, cache.DocTag(id.Hex()))
+	}
+	cache.InvalidateTags(tags...)
+}
+
 func SaveGameItem(item *model.GameItem) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -120,14 +229,36 @@ func SaveGameItem(item *model.GameItem) error {
 	item.Size = strings.Replace(item.Size, "mb", "MB", -1)
 	filter := bson.M{"_id": item.ID}
 	update := bson.M{"$set": item}
-	opts := options.Update().SetUpsert(true)
-	_, err := GameItemCollection.UpdateOne(ctx, filter, update, opts)
-	if err != nil {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.Before).
+		SetProjection(bson.M{"raw_name": 1, "size": 1, "download": 1, "update_flag": 1})
+	var old model.GameItem
+	err := GameItemCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&old)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
 		return err
 	}
+	invalidateGameItems(item.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		events.Publish(events.ItemCreated, *item)
+		return nil
+	}
+	if gameItemChanged(&old, item) {
+		events.Publish(events.ItemUpdated, *item)
+	}
 	return nil
 }
 
+// gameItemChanged reports whether saving item over old changed the repack itself.
+// The modified count of the write can not tell, updated_at is set on every save so it is always 1.
+// Renaming an item, e.g. by the format command, is not an update of the repack either.
+func gameItemChanged(old *model.GameItem, item *model.GameItem) bool {
+	return old.RawName != item.RawName ||
+		old.Size != item.Size ||
+		old.Download != item.Download ||
+		old.UpdateFlag != item.UpdateFlag
+}
+
 func SaveGameInfo(item *model.GameInfo) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -140,14 +271,35 @@ func SaveGameInfo(item *model.GameInfo) error {
 	item.UpdatedAt = time.Now()
 	filter := bson.M{"_id": item.ID}
 	update := bson.M{"$set": item}
-	opts := options.Update().SetUpsert(true)
-	_, err := GameInfoCollection.UpdateOne(ctx, filter, update, opts)
-	if err != nil {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.Before).
+		SetProjection(bson.M{"games": 1})
+	var old model.GameInfo
+	err := GameInfoCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&old)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
 		return err
 	}
+	indexGameInfoName(item)
+	invalidateGameInfos(item.ID)
+	if linked := newGameIDs(old.GameIDs, item.GameIDs); len(linked) > 0 {
+		info := *item
+		events.Publish(events.InfoOrganized, events.InfoOrganizedData{Info: &info, GameIDs: linked})
+	}
 	return nil
 }
 
+// newGameIDs returns the ids in current that are not in old
+func newGameIDs(old []primitive.ObjectID, current []primitive.ObjectID) []primitive.ObjectID {
+	var res []primitive.ObjectID
+	for _, id := range current {
+		if !slices.Contains(old, id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
 func GetAllGameItems() ([]*model.GameItem, error) {
 	var items []*model.GameItem
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -219,83 +371,299 @@ func GetGameItemsByIDs(ids []primitive.ObjectID) ([]*model.GameItem, error) {
 	return items, err
 }
 
-func SearchGameInfos(name string, page int, pageSize int) ([]*model.GameInfo, int, error) {
-	var items []*model.GameInfo
-	name = removeDelimiter.ReplaceAllString(name, " ")
-	name = removeRepeatingSpacesRegex.ReplaceAllString(name, " ")
-	name = strings.TrimSpace(name)
-	name = strings.Replace(name, " ", ".*", -1)
-	name = fmt.Sprintf("%s.*", name)
+const fuzzySearchLimit = 50
+
+// fields limits the returned fields of each game info, nil returns every field,
+// see ParseGameInfoFields. Facets are only counted when withFacets is set, they are nil otherwise.
+func SearchGameInfos(name string, filter *model.SearchFilter, page *Page, fields []string, withFacets bool) ([]*model.GameInfo, string, int64, *model.SearchFacets, error) {
+	items, next, total, facets, err := searchGameInfos(buildSearchMatch(name, filter), filter, nil, page, fields, withFacets)
+	if err != nil || total > 0 || strings.TrimSpace(name) == "" {
+		return items, next, total, facets, err
+	}
+	// nothing matched the keyword, retry with typo tolerant matches from the name index
+	matches, err := FuzzySearchGameInfoNames(name, fuzzySearchLimit)
+	if err != nil || len(matches) == 0 {
+		return items, next, total, facets, nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.Key)
+	}
+	match := buildSearchMatch("", filter)
+	match["_id"] = bson.M{"$in": ids}
+	return searchGameInfos(match, filter, ids, page, fields, withFacets)
+}
+
+// rankedIDs keeps the results in the given order instead of sorting by name,
+// such results are paged by offset
+func searchGameInfos(match bson.M, filter *model.SearchFilter, rankedIDs []primitive.ObjectID, page *Page, fields []string, withFacets bool) ([]*model.GameInfo, string, int64, *model.SearchFacets, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	filter := bson.M{"$or": []interface{}{
-		bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
-		bson.M{"aliases": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
-	}}
-	totalCount, err := GameInfoCollection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
+	pipeline := mongo.Pipeline{}
+	if len(match) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: match}})
+	}
+	// the items are only joined for the item filters and the author facet
+	itemMatch := buildSearchItemMatch(filter)
+	if len(itemMatch) > 0 || withFacets {
+		pipeline = append(pipeline, searchItemsLookupStage)
+	}
+	if len(itemMatch) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{
+			"search_items": bson.M{"$elemMatch": itemMatch},
+		}}})
+	}
+	var results bson.A
+	if len(rankedIDs) > 0 {
+		results = bson.A{
+			bson.M{"$addFields": bson.M{"search_rank": bson.M{"$indexOfArray": bson.A{rankedIDs, "$_id"}}}},
+			bson.M{"$sort": bson.D{{Key: "search_rank", Value: 1}}},
+			bson.M{"$skip": int64(page.Offset)},
+		}
+	} else {
+		if after := page.afterNameID("name"); after != nil {
+			results = append(results, bson.M{"$match": after})
+		}
+		results = append(results,
+			bson.M{"$sort": bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+			bson.M{"$skip": page.skip()},
+		)
+	}
+	results = append(results, bson.M{"$limit": int64(page.Limit + 1)})
+	if projection := gameInfoProjection(fields); projection != nil {
+		results = append(results, bson.M{"$project": projection})
+	} else {
+		results = append(results, bson.M{"$project": bson.M{"search_items": 0, "search_rank": 0}})
+	}
+	if NeedGameItems(fields) {
+		results = append(results, gameItemsLookupStage)
+	}
+	facet := bson.M{
+		"results": results,
+		"total": bson.A{
+			bson.M{"$count": "count"},
+		},
+	}
+	if withFacets {
+		facet["authors"] = bson.A{
+			bson.M{"$project": bson.M{"author": bson.M{"$setUnion": bson.A{"$search_items.author", bson.A{}}}}},
+			bson.M{"$unwind": "$author"},
+			bson.M{"$group": bson.M{"_id": "$author", "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+		facet["developers"] = facetPipeline("$developers")
+		facet["publishers"] = facetPipeline("$publishers")
+		facet["languages"] = facetPipeline("$languages")
+		facet["has_steam_id"] = facetPipeline(bson.M{"$toString": bson.M{"$gt": bson.A{"$steam_id", 0}}})
 	}
-	totalPage := (totalCount + int64(pageSize) - 1) / int64(pageSize)
-	findOpts := options.Find().SetSkip(int64((page - 1) * pageSize)).SetLimit(int64(pageSize)).SetSort(bson.D{{Key: "name", Value: 1}})
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: facet}})
 
-	cursor, err := GameInfoCollection.Find(ctx, filter, findOpts)
+	cursor, err := GameInfoCollection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, 0, err
+		return nil, "", 0, nil, err
 	}
 	defer cursor.Close(ctx)
-	for cursor.Next(ctx) {
-		var game model.GameInfo
-		if err = cursor.Decode(&game); err != nil {
-			return nil, 0, err
+	var res []struct {
+		Results []*gameInfoWithItems `bson:"results"`
+		Total   []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		model.SearchFacets `bson:",inline"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, "", 0, nil, err
+	}
+	var facets *model.SearchFacets
+	if withFacets {
+		facets = &model.SearchFacets{}
+		if len(res) > 0 {
+			facets = &res[0].SearchFacets
 		}
-		game.Games, err = GetGameItemsByIDs(game.GameIDs)
-		if err != nil {
-			return nil, 0, err
+	}
+	if len(res) == 0 {
+		return nil, "", 0, facets, nil
+	}
+	totalCount := int64(0)
+	if len(res[0].Total) > 0 {
+		totalCount = res[0].Total[0].Count
+	}
+	data, more := trimPage(res[0].Results, page.Limit)
+	items := make([]*model.GameInfo, 0, len(data))
+	for _, result := range data {
+		result.GameInfo.Games = result.Items
+		items = append(items, &result.GameInfo)
+	}
+	next := ""
+	if more {
+		if len(rankedIDs) > 0 {
+			next = (&Cursor{Offset: page.Offset + len(items)}).String()
+		} else {
+			last := items[len(items)-1]
+			next = nameIDCursor(last.Name, last.ID)
 		}
-		items = append(items, &game)
 	}
-	if err := cursor.Err(); err != nil {
-		return nil, 0, err
+	return items, next, totalCount, facets, nil
+}
+
+// search_items only carries the fields needed for filtering and facets,
+// the full downloads are loaded for the current page only
+var searchItemsLookupStage = bson.D{{Key: "$lookup", Value: bson.M{
+	"from": gameDownloadCollectionName,
+	"let":  bson.M{"ids": "$games"},
+	"pipeline": bson.A{
+		bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", bson.M{"$ifNull": bson.A{"$$ids", bson.A{}}}}}}},
+		bson.M{"$project": bson.M{
+			"author":     1,
+			"size_bytes": sizeBytesExpr,
+		}},
+	},
+	"as": "search_items",
+}}}
+
+// parse "12.5 GB", "from 9.8 GB", "12,5 GB" into bytes, null if unparseable
+var sizeBytesExpr = bson.M{"$let": bson.M{
+	"vars": bson.M{"m": bson.M{"$regexFind": bson.M{
+		"input":   bson.M{"$ifNull": bson.A{"$size", ""}},
+		"regex":   `([0-9]+(?:[.,][0-9]+)?)\s*(TB|GB|MB|KB)`,
+		"options": "i",
+	}}},
+	"in": bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$$m", nil}},
+		nil,
+		bson.M{"$multiply": bson.A{
+			bson.M{"$convert": bson.M{
+				"input": bson.M{"$replaceAll": bson.M{
+					"input":       bson.M{"$arrayElemAt": bson.A{"$$m.captures", 0}},
+					"find":        ",",
+					"replacement": ".",
+				}},
+				"to":      "double",
+				"onError": nil,
+				"onNull":  nil,
+			}},
+			bson.M{"$switch": bson.M{
+				"branches": bson.A{
+					bson.M{"case": bson.M{"$eq": bson.A{bson.M{"$toUpper": bson.M{"$arrayElemAt": bson.A{"$$m.captures", 1}}}, "TB"}}, "then": 1 << 40},
+					bson.M{"case": bson.M{"$eq": bson.A{bson.M{"$toUpper": bson.M{"$arrayElemAt": bson.A{"$$m.captures", 1}}}, "GB"}}, "then": 1 << 30},
+					bson.M{"case": bson.M{"$eq": bson.A{bson.M{"$toUpper": bson.M{"$arrayElemAt": bson.A{"$$m.captures", 1}}}, "MB"}}, "then": 1 << 20},
+				},
+				"default": 1 << 10,
+			}},
+		}},
+	}},
+}}
+
+func facetPipeline(field interface{}) bson.A {
+	return bson.A{
+		bson.M{"$project": bson.M{"value": field}},
+		bson.M{"$unwind": "$value"},
+		bson.M{"$match": bson.M{"value": bson.M{"$nin": bson.A{nil, ""}}}},
+		bson.M{"$group": bson.M{"_id": "$value", "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+func buildSearchMatch(name string, filter *model.SearchFilter) bson.M {
+	match := bson.M{}
+	name = removeDelimiter.ReplaceAllString(name, " ")
+	name = removeRepeatingSpacesRegex.ReplaceAllString(name, " ")
+	name = strings.TrimSpace(name)
+	if name != "" {
+		name = strings.Replace(name, " ", ".*", -1)
+		name = fmt.Sprintf("%s.*", name)
+		match["$or"] = []interface{}{
+			bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
+			bson.M{"aliases": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
+		}
+	}
+	if filter == nil {
+		return match
+	}
+	if filter.Developer != "" {
+		match["developers"] = exactRegex(filter.Developer)
+	}
+	if filter.Publisher != "" {
+		match["publishers"] = exactRegex(filter.Publisher)
+	}
+	if filter.Language != "" {
+		match["languages"] = exactRegex(filter.Language)
 	}
-	return items, int(totalPage), nil
+	if filter.HasSteamID != nil {
+		if *filter.HasSteamID {
+			match["steam_id"] = bson.M{"$gt": 0}
+		} else {
+			match["steam_id"] = bson.M{"$not": bson.M{"$gt": 0}}
+		}
+	}
+	if !filter.UpdatedSince.IsZero() {
+		match["updated_at"] = bson.M{"$gte": filter.UpdatedSince}
+	}
+	return match
+}
+
+func buildSearchItemMatch(filter *model.SearchFilter) bson.M {
+	match := bson.M{}
+	if filter == nil {
+		return match
+	}
+	if filter.Author != "" {
+		match["author"] = exactRegex(filter.Author)
+	}
+	size := bson.M{}
+	if filter.MinSize > 0 {
+		size["$gte"] = filter.MinSize
+	}
+	if filter.MaxSize > 0 {
+		size["$lte"] = filter.MaxSize
+	}
+	if len(size) > 0 {
+		match["size_bytes"] = size
+	}
+	return match
+}
+
+func exactRegex(value string) primitive.Regex {
+	return primitive.Regex{Pattern: fmt.Sprintf("^%s$", regexp.QuoteMeta(strings.TrimSpace(value))), Options: "i"}
 }
 
-func SearchGameInfosCache(name string, page int, pageSize int) ([]*model.GameInfo, int, error) {
+func SearchGameInfosCache(name string, filter *model.SearchFilter, page *Page, fields []string, withFacets bool) ([]*model.GameInfo, string, int64, *model.SearchFacets, error) {
 	type res struct {
-		Items     []*model.GameInfo
-		TotalPage int
+		Items      []*model.GameInfo
+		NextCursor string
+		Total      int64
+		Facets     *model.SearchFacets
 	}
 	name = strings.ToLower(name)
-	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("searchGameDetails:%s:%d:%d", name, page, pageSize)
-		val, exist := cache.Get(key)
-		if exist {
-			var data res
-			err := json.Unmarshal([]byte(val), &data)
-			if err != nil {
-				return nil, 0, err
-			}
-			return data.Items, data.TotalPage, nil
-		} else {
-			data, totalPage, err := SearchGameInfos(name, page, pageSize)
-			if err != nil {
-				return nil, 0, err
-			}
-			dataBytes, err := json.Marshal(res{Items: data, TotalPage: totalPage})
-			if err != nil {
-				return nil, 0, err
-			}
-			_ = cache.AddWithExpire(key, string(dataBytes), 5*time.Minute)
-			return data, totalPage, nil
+	filterBytes, err := json.Marshal(filter)
+	if err != nil {
+		return nil, "", 0, nil, err
+	}
+	pageBytes, err := json.Marshal(page)
+	if err != nil {
+		return nil, "", 0, nil, err
+	}
+	key := cache.NamespaceSearch.Key(name, string(filterBytes), string(pageBytes), strings.Join(fields, ","), strconv.FormatBool(withFacets))
+	data, err := cache.GetOrLoadTagged(key, 5*time.Minute, func() (res, []string, error) {
+		items, next, total, facets, err := SearchGameInfos(name, filter, page, fields, withFacets)
+		if err != nil {
+			return res{}, nil, err
 		}
-	} else {
-		return SearchGameInfos(name, page, pageSize)
+		// a page is dropped once any game on it changes, newly matching games show up after the TTL
+		tags := make([]string, 0, len(items))
+		for _, item := range items {
+			tags = append(tags, cache.DocTag(item.ID.Hex()))
+		}
+		return res{Items: items, NextCursor: next, Total: total, Facets: facets}, tags, nil
+	})
+	if err != nil {
+		return nil, "", 0, nil, err
 	}
+	return data.Items, data.NextCursor, data.Total, data.Facets, nil
 }
 
-func GetGameInfoByPlatformID(platform string, id int) (*model.GameInfo, error) {
+// fields limits the loaded fields, nil loads every field, see ParseGameInfoFields
+func GetGameInfoByPlatformID(platform string, id int, fields []string) (*model.GameInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	var filter interface{}
@@ -308,13 +676,76 @@ func GetGameInfoByPlatformID(platform string, id int) (*model.GameInfo, error) {
 		filter = bson.M{"igdb_id": id}
 	}
 	var game model.GameInfo
-	err := GameInfoCollection.FindOne(ctx, filter).Decode(&game)
+	err := GameInfoCollection.FindOne(ctx, filter, findOneProjection(fields)).Decode(&game)
 	if err != nil {
 		return nil, err
 	}
 	return &game, nil
 }
 
+// gameItemsLookupStage embeds the downloads of each game info as game_items
+var gameItemsLookupStage = bson.D{{Key: "$lookup", Value: bson.D{
+	{Key: "from", Value: gameDownloadCollectionName},
+	{Key: "localField", Value: "games"},
+	{Key: "foreignField", Value: "_id"},
+	{Key: "as", Value: "game_items"},
+}}}
+
+type gameInfoWithItems struct {
+	model.GameInfo `bson:",inline"`
+	Items          []*model.GameItem `bson:"game_items"`
+}
+
+// GetGameInfosByKeys returns the game infos matching any of the given IDs,
+// with their downloads embedded, in a single aggregation
+func GetGameInfosByKeys(ids []primitive.ObjectID, steamIDs []int, igdbIDs []int, fields []string) ([]*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	or := bson.A{}
+	if len(ids) > 0 {
+		or = append(or, bson.M{"_id": bson.M{"$in": ids}})
+	}
+	if len(steamIDs) > 0 {
+		or = append(or, bson.M{"steam_id": bson.M{"$in": steamIDs}})
+	}
+	if len(igdbIDs) > 0 {
+		or = append(or, bson.M{"igdb_id": bson.M{"$in": igdbIDs}})
+	}
+	if len(or) == 0 {
+		return nil, nil
+	}
+	projection := gameInfoProjection(fields)
+	if projection != nil {
+		// not_found is computed from the platform IDs
+		projection["steam_id"] = 1
+		projection["igdb_id"] = 1
+	}
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"$or": or}}},
+	}
+	if projection != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projection}})
+	}
+	if NeedGameItems(fields) {
+		pipeline = append(pipeline, gameItemsLookupStage)
+	}
+	cursor, err := GameInfoCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var data []*gameInfoWithItems
+	if err = cursor.All(ctx, &data); err != nil {
+		return nil, err
+	}
+	res := make([]*model.GameInfo, 0, len(data))
+	for _, item := range data {
+		item.GameInfo.Games = item.Items
+		res = append(res, &item.GameInfo)
+	}
+	return res, nil
+}
+
 func GetUnorganizedGameItems(num int) ([]*model.GameItem, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -358,17 +789,109 @@ func GetUnorganizedGameItems(num int) ([]*model.GameItem, error) {
 	return gamesNotInDetails, nil
 }
 
-func GetGameInfoByID(id primitive.ObjectID) (*model.GameInfo, error) {
+func GetUnorganizedGameItemsPage(page *Page) ([]*model.GameItem, string, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	results := bson.A{}
+	if after := page.afterNameID("name"); after != nil {
+		results = append(results, bson.M{"$match": after})
+	}
+	results = append(results,
+		bson.M{"$sort": bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+		bson.M{"$skip": page.skip()},
+		bson.M{"$limit": int64(page.Limit + 1)},
+		bson.M{"$project": bson.M{"gameDetail": 0}},
+	)
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: gameInfoCollectionName},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "games"},
+			{Key: "as", Value: "gameDetail"},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "gameDetail", Value: bson.D{{Key: "$size", Value: 0}}},
+		}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"results": results,
+			"total":   bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+	cursor, err := GameItemCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer cursor.Close(ctx)
+	var res []struct {
+		Results []*model.GameItem `bson:"results"`
+		Total   []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, "", 0, err
+	}
+	if len(res) == 0 {
+		return nil, "", 0, nil
+	}
+	totalCount := int64(0)
+	if len(res[0].Total) > 0 {
+		totalCount = res[0].Total[0].Count
+	}
+	items, more := trimPage(res[0].Results, page.Limit)
+	next := ""
+	if more {
+		last := items[len(items)-1]
+		next = nameIDCursor(last.Name, last.ID)
+	}
+	return items, next, totalCount, nil
+}
+
+// fields limits the loaded fields, nil loads every field, see ParseGameInfoFields
+func GetGameInfoByID(id primitive.ObjectID, fields []string) (*model.GameInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	var game model.GameInfo
-	err := GameInfoCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&game)
+	err := GameInfoCollection.FindOne(ctx, bson.M{"_id": id}, findOneProjection(fields)).Decode(&game)
 	if err != nil {
 		return nil, err
 	}
 	return &game, nil
 }
 
+// GetGameInfosByIDs returns the game infos of ids in the order of ids, missing ones are left out.
+// fields limits the loaded fields, nil loads every field.
+func GetGameInfosByIDs(ids []primitive.ObjectID, fields []string) ([]*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	opts := options.Find()
+	if projection := gameInfoProjection(fields); projection != nil {
+		opts.SetProjection(projection)
+	}
+	cursor, err := GameInfoCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var infos []*model.GameInfo
+	if err = cursor.All(ctx, &infos); err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*model.GameInfo, len(infos))
+	for _, info := range infos {
+		byID[info.ID] = info
+	}
+	res := make([]*model.GameInfo, 0, len(infos))
+	for _, id := range ids {
+		if info, ok := byID[id]; ok {
+			res = append(res, info)
+		}
+	}
+	return res, nil
+}
+
 func DeduplicateGames() ([]primitive.ObjectID, error) {
 	type queryRes struct {
 		ID    string               `bson:"_id"`
@@ -406,6 +929,7 @@ func DeduplicateGames() ([]primitive.ObjectID, error) {
 		if err != nil {
 			return nil, err
 		}
+		invalidateGameItems(idsToDelete...)
 		cursor, err := GameInfoCollection.Find(ctx, bson.M{"games": bson.M{"$in": idsToDelete}})
 		if err != nil {
 			return nil, err
@@ -463,7 +987,7 @@ func CleanOrphanGamesInGameInfos() (map[primitive.ObjectID]primitive.ObjectID, e
 	}
 	var res = make(map[primitive.ObjectID]primitive.ObjectID)
 	for _, item := range qres {
-		info, err := GetGameInfoByID(item.ID)
+		info, err := GetGameInfoByID(item.ID, nil)
 		if err != nil {
 			continue
 		}
@@ -502,16 +1026,25 @@ func CleanGameInfoWithEmptyGameIDs() ([]primitive.ObjectID, error) {
 	if err != nil {
 		return nil, err
 	}
+	for _, id := range res {
+		unindexGameInfoName(id)
+	}
+	invalidateGameInfos(res...)
 	return res, nil
 }
 
-func GetGameInfosByName(name string) ([]*model.GameInfo, error) {
+// fields limits the loaded fields, nil loads every field, see ParseGameInfoFields
+func GetGameInfosByName(name string, fields []string) ([]*model.GameInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	name = strings.TrimSpace(name)
 	name = fmt.Sprintf("^%s$", name)
 	filter := bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}}
-	cursor, err := GameInfoCollection.Find(ctx, filter)
+	opts := options.Find()
+	if projection := gameInfoProjection(fields); projection != nil {
+		opts.SetProjection(projection)
+	}
+	cursor, err := GameInfoCollection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -579,7 +1112,7 @@ func MergeSameNameGameInfos() error {
 		otherPlatformItems := make([]*model.GameInfo, 0)
 		skip := false
 		for _, id := range ids {
-			item, err := GetGameInfoByID(id)
+			item, err := GetGameInfoByID(id, nil)
 			if err != nil {
 				continue
 			}
@@ -658,6 +1191,8 @@ func DeleteGameInfoByID(id primitive.ObjectID) error {
 	if err != nil {
 		return err
 	}
+	unindexGameInfoName(id)
+	invalidateGameInfos(id)
 	return nil
 }
 
@@ -668,6 +1203,7 @@ func DeleteGameItemByID(id primitive.ObjectID) error {
 	if err != nil {
 		return err
 	}
+	invalidateGameItems(id)
 	filter := bson.M{"games": bson.M{"$in": []primitive.ObjectID{id}}}
 	cursor, err := GameInfoCollection.Find(ctx, filter)
 	if err != nil {
@@ -719,6 +1255,62 @@ func GetAllAuthors() ([]string, error) {
 	return res, nil
 }
 
+func GetAuthorsPage(page *Page) ([]string, string, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	results := bson.A{}
+	if page.After != nil && page.After.Name != "" {
+		results = append(results, bson.M{"$match": bson.M{"_id": bson.M{"$gt": page.After.Name}}})
+	}
+	results = append(results,
+		bson.M{"$skip": page.skip()},
+		bson.M{"$limit": int64(page.Limit + 1)},
+	)
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$author"},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"results": results,
+			"total":   bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+	cursor, err := GameItemCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer cursor.Close(ctx)
+	var res []struct {
+		Results []struct {
+			Author string `bson:"_id"`
+		} `bson:"results"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, "", 0, err
+	}
+	if len(res) == 0 {
+		return nil, "", 0, nil
+	}
+	totalCount := int64(0)
+	if len(res[0].Total) > 0 {
+		totalCount = res[0].Total[0].Count
+	}
+	var authors []string
+	for _, author := range res[0].Results {
+		authors = append(authors, author.Author)
+	}
+	authors, more := trimPage(authors, page.Limit)
+	next := ""
+	if more {
+		next = (&Cursor{Name: authors[len(authors)-1]}).String()
+	}
+	return authors, next, totalCount, nil
+}
+
 func GetAllGameInfos() ([]*model.GameInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
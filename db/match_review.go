@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// QueueMatchReview adds the game item of review to the review queue,
+// replacing the candidates and status of an earlier review of the same item
+func QueueMatchReview(review *model.MatchReview) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	now := time.Now()
+	review.Status = model.MatchReviewPending
+	review.UpdatedAt = now
+	filter := bson.M{"game_id": review.GameID}
+	update := bson.M{
+		"$set": bson.M{
+			"name":       review.Name,
+			"raw_name":   review.RawName,
+			"author":     review.Author,
+			"reason":     review.Reason,
+			"error":      review.Error,
+			"candidates": review.Candidates,
+			"status":     review.Status,
+			"updated_at": review.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return MatchReviewCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(review)
+}
+
+func GetMatchReviewByGameID(gameID primitive.ObjectID) (*model.MatchReview, error) {
+	var review model.MatchReview
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := MatchReviewCollection.FindOne(ctx, bson.M{"game_id": gameID}).Decode(&review)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func GetMatchReviewByID(id primitive.ObjectID) (*model.MatchReview, error) {
+	var review model.MatchReview
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := MatchReviewCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&review)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetMatchReviewsPage returns the reviews with status, oldest first, with their game items
+func GetMatchReviewsPage(status string, page *Page) ([]*model.MatchReview, string, int64, error) {
+	var res []*model.MatchReview
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.M{"status": status}
+	total, err := MatchReviewCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if page.After != nil && page.After.ID != nil {
+		filter["_id"] = bson.M{"$gt": *page.After.ID}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(page.skip()).
+		SetLimit(int64(page.Limit + 1))
+	cursor, err := MatchReviewCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, "", 0, err
+	}
+	res, more := trimPage(res, page.Limit)
+	next := ""
+	if more {
+		id := res[len(res)-1].ID
+		next = (&Cursor{ID: &id}).String()
+	}
+	gameIDs := make([]primitive.ObjectID, 0, len(res))
+	for _, review := range res {
+		gameIDs = append(gameIDs, review.GameID)
+	}
+	games, err := GetGameItemsByIDs(gameIDs)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	gameMap := make(map[primitive.ObjectID]*model.GameItem, len(games))
+	for _, game := range games {
+		gameMap[game.ID] = game
+	}
+	for _, review := range res {
+		review.Game = gameMap[review.GameID]
+	}
+	return res, next, total, nil
+}
+
+// SetMatchReviewStatus records the decision on a review, it returns mongo.ErrNoDocuments
+// if the review does not exist or is not pending
+func SetMatchReviewStatus(id primitive.ObjectID, status string, reviewer string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := MatchReviewCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.MatchReviewPending},
+		bson.M{"$set": bson.M{"status": status, "reviewed_by": reviewer, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReopenMatchReview puts an accepted review back in the queue,
+// for reviews claimed by a reviewer whose link failed
+func ReopenMatchReview(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := MatchReviewCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.MatchReviewAccepted},
+		bson.M{
+			"$set":   bson.M{"status": model.MatchReviewPending, "updated_at": time.Now()},
+			"$unset": bson.M{"reviewed_by": ""},
+		},
+	)
+	return err
+}
+
+// ResolveMatchReviewOfGame marks the pending review of a game item as accepted,
+// for game items organized by other means than the review
+func ResolveMatchReviewOfGame(gameID primitive.ObjectID, reviewer string) error {
+	review, err := GetMatchReviewByGameID(gameID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+	if review.Status != model.MatchReviewPending {
+		return nil
+	}
+	err = SetMatchReviewStatus(review.ID, model.MatchReviewAccepted, reviewer)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const nameAliasBatchSize = 1000
+
+// SaveNameAlias adds alias or points the existing alias of the same name to its game
+func SaveNameAlias(alias *model.NameAlias) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	alias.UpdatedAt = time.Now()
+	if alias.CreatedAt.IsZero() {
+		alias.CreatedAt = alias.UpdatedAt
+	}
+	_, err := NameAliasCollection.UpdateOne(ctx, bson.M{"_id": alias.Name}, nameAliasUpdate(alias, true), options.Update().SetUpsert(true))
+	return err
+}
+
+func nameAliasUpdate(alias *model.NameAlias, overwrite bool) bson.M {
+	fields := bson.M{
+		"game_name":   alias.GameName,
+		"platform":    alias.Platform,
+		"platform_id": alias.PlatformID,
+		"source":      alias.Source,
+		"updated_at":  alias.UpdatedAt,
+	}
+	if !overwrite {
+		fields["created_at"] = alias.CreatedAt
+		return bson.M{"$setOnInsert": fields}
+	}
+	return bson.M{
+		"$set":         fields,
+		"$setOnInsert": bson.M{"created_at": alias.CreatedAt},
+	}
+}
+
+// SaveNameAliases imports aliases, replacing the aliases of the same names only if overwrite is set.
+// It returns the number of aliases added or changed.
+func SaveNameAliases(aliases []*model.NameAlias, overwrite bool) (int, error) {
+	count := 0
+	for start := 0; start < len(aliases); start += nameAliasBatchSize {
+		end := min(start+nameAliasBatchSize, len(aliases))
+		models := make([]mongo.WriteModel, 0, end-start)
+		for _, alias := range aliases[start:end] {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": alias.Name}).
+				SetUpdate(nameAliasUpdate(alias, overwrite)).
+				SetUpsert(true))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		res, err := NameAliasCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		cancel()
+		if err != nil {
+			return count, err
+		}
+		count += int(res.UpsertedCount + res.ModifiedCount)
+	}
+	return count, nil
+}
+
+func GetNameAlias(name string) (*model.NameAlias, error) {
+	var alias model.NameAlias
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := NameAliasCollection.FindOne(ctx, bson.M{"_id": name}).Decode(&alias)
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func GetAllNameAliases() ([]*model.NameAlias, error) {
+	aliases := []*model.NameAlias{}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cursor, err := NameAliasCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// DeleteNameAlias returns mongo.ErrNoDocuments if there is no alias of name
+func DeleteNameAlias(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := NameAliasCollection.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
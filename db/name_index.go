@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	gameInfoNameIndex      atomic.Pointer[utils.TrigramIndex[primitive.ObjectID]]
+	gameInfoNameIndexMutx  = &sync.Mutex{}
+	gameInfoNameIndexBuilt bool
+
+	// gameInfoNameChanges records the names saved (nil when deleted) while a load reads the database,
+	// it is nil when no load is running. Its mutex also guards swapping the index.
+	gameInfoNameChanges     map[primitive.ObjectID][]string
+	gameInfoNameChangesMutx = &sync.Mutex{}
+)
+
+func init() {
+	gameInfoNameIndex.Store(utils.NewTrigramIndex[primitive.ObjectID]())
+}
+
+const fuzzySearchMinScore = 0.45
+
+// LoadGameInfoNameIndex builds the in-memory name index from the database if it is not built yet.
+// SaveGameInfo and the delete functions keep it up to date afterwards.
+func LoadGameInfoNameIndex() error {
+	gameInfoNameIndexMutx.Lock()
+	defer gameInfoNameIndexMutx.Unlock()
+	if gameInfoNameIndexBuilt {
+		return nil
+	}
+	if err := loadGameInfoNameIndex(readGameInfoNameIndex); err != nil {
+		return err
+	}
+	gameInfoNameIndexBuilt = true
+	return nil
+}
+
+// ReloadGameInfoNameIndex rebuilds the name index from the database, so that the game infos
+// saved or deleted by other processes are found. Searches use the previous index until it is done.
+func ReloadGameInfoNameIndex() error {
+	gameInfoNameIndexMutx.Lock()
+	defer gameInfoNameIndexMutx.Unlock()
+	if err := loadGameInfoNameIndex(readGameInfoNameIndex); err != nil {
+		return err
+	}
+	gameInfoNameIndexBuilt = true
+	return nil
+}
+
+// loadGameInfoNameIndex swaps in the index built by read, with the changes made while it ran
+func loadGameInfoNameIndex(read func() (*utils.TrigramIndex[primitive.ObjectID], error)) error {
+	gameInfoNameChangesMutx.Lock()
+	gameInfoNameChanges = make(map[primitive.ObjectID][]string)
+	gameInfoNameChangesMutx.Unlock()
+	index, err := read()
+	gameInfoNameChangesMutx.Lock()
+	defer gameInfoNameChangesMutx.Unlock()
+	changes := gameInfoNameChanges
+	gameInfoNameChanges = nil
+	if err != nil {
+		return err
+	}
+	// the documents read may predate the saves and deletes made during the load
+	for id, names := range changes {
+		if names == nil {
+			index.Remove(id)
+		} else {
+			index.Add(id, names...)
+		}
+	}
+	gameInfoNameIndex.Store(index)
+	return nil
+}
+
+func readGameInfoNameIndex() (*utils.TrigramIndex[primitive.ObjectID], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	opts := options.Find().SetProjection(bson.M{"name": 1, "aliases": 1})
+	cursor, err := GameInfoCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	index := utils.NewTrigramIndex[primitive.ObjectID]()
+	for cursor.Next(ctx) {
+		var info model.GameInfo
+		if err := cursor.Decode(&info); err != nil {
+			return nil, err
+		}
+		index.Add(info.ID, gameInfoNames(&info)...)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func gameInfoNames(info *model.GameInfo) []string {
+	return append([]string{info.Name}, info.Aliases...)
+}
+
+func indexGameInfoName(info *model.GameInfo) {
+	names := gameInfoNames(info)
+	gameInfoNameChangesMutx.Lock()
+	defer gameInfoNameChangesMutx.Unlock()
+	if gameInfoNameChanges != nil {
+		gameInfoNameChanges[info.ID] = names
+	}
+	gameInfoNameIndex.Load().Add(info.ID, names...)
+}
+
+func unindexGameInfoName(id primitive.ObjectID) {
+	gameInfoNameChangesMutx.Lock()
+	defer gameInfoNameChangesMutx.Unlock()
+	if gameInfoNameChanges != nil {
+		gameInfoNameChanges[id] = nil
+	}
+	gameInfoNameIndex.Load().Remove(id)
+}
+
+// FuzzySearchGameInfoNames returns the best typo tolerant matches for name from the name index.
+func FuzzySearchGameInfoNames(name string, limit int) ([]utils.TrigramMatch[primitive.ObjectID], error) {
+	if err := LoadGameInfoNameIndex(); err != nil {
+		return nil, err
+	}
+	return gameInfoNameIndex.Load().Search(name, limit, fuzzySearchMinScore), nil
+}
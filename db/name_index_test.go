@@ -0,0 +1,117 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLoadGameInfoNameIndexKeepsChanges(t *testing.T) {
+	old := gameInfoNameIndex.Load()
+	t.Cleanup(func() { gameInfoNameIndex.Store(old) })
+
+	id := primitive.NewObjectID()
+	stale := &model.GameInfo{ID: id, Name: "Hollow Knight"}
+	tests := []struct {
+		name string
+		// read is the game info the database returns, nil if it has none
+		read *model.GameInfo
+		// during changes the index while the database is read
+		during  func()
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:  "read",
+			read:  stale,
+			query: "hollow knight",
+			want:  true,
+		},
+		{
+			name:   "saved during load",
+			during: func() { indexGameInfoName(&model.GameInfo{ID: id, Name: "Hollow Knight"}) },
+			query:  "hollow knight",
+			want:   true,
+		},
+		{
+			name:   "renamed during load",
+			read:   stale,
+			during: func() { indexGameInfoName(&model.GameInfo{ID: id, Name: "Hollow Knight: Silksong"}) },
+			query:  "silksong",
+			want:   true,
+		},
+		{
+			name:   "old name after rename",
+			read:   stale,
+			during: func() { indexGameInfoName(&model.GameInfo{ID: id, Name: "Terraria"}) },
+			query:  "hollow knight",
+		},
+		{
+			name:   "deleted during load",
+			read:   stale,
+			during: func() { unindexGameInfoName(id) },
+			query:  "hollow knight",
+		},
+		{
+			name:   "deleted and saved again",
+			read:   stale,
+			during: func() { unindexGameInfoName(id); indexGameInfoName(stale) },
+			query:  "hollow knight",
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameInfoNameIndex.Store(utils.NewTrigramIndex[primitive.ObjectID]())
+			err := loadGameInfoNameIndex(func() (*utils.TrigramIndex[primitive.ObjectID], error) {
+				index := utils.NewTrigramIndex[primitive.ObjectID]()
+				if tt.read != nil {
+					index.Add(tt.read.ID, gameInfoNames(tt.read)...)
+				}
+				if tt.during != nil {
+					tt.during()
+				}
+				return index, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gameInfoNameChanges != nil {
+				t.Errorf("changes are still recorded after the load")
+			}
+			matches := gameInfoNameIndex.Load().Search(tt.query, 10, fuzzySearchMinScore)
+			found := len(matches) > 0 && matches[0].Key == id
+			if found != tt.want {
+				t.Errorf("got matches %+v, want found %v", matches, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadGameInfoNameIndexError(t *testing.T) {
+	old := gameInfoNameIndex.Load()
+	t.Cleanup(func() { gameInfoNameIndex.Store(old) })
+
+	current := utils.NewTrigramIndex[primitive.ObjectID]()
+	id := primitive.NewObjectID()
+	current.Add(id, "Hollow Knight")
+	gameInfoNameIndex.Store(current)
+	readErr := errors.New("read failed")
+	err := loadGameInfoNameIndex(func() (*utils.TrigramIndex[primitive.ObjectID], error) {
+		return nil, readErr
+	})
+	if !errors.Is(err, readErr) {
+		t.Fatalf("got error %v, want %v", err, readErr)
+	}
+	if gameInfoNameIndex.Load() != current {
+		t.Errorf("index was replaced after a failed load")
+	}
+	if gameInfoNameChanges != nil {
+		t.Errorf("changes are still recorded after a failed load")
+	}
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func SaveNotifier(notifier *model.Notifier) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if notifier.ID.IsZero() {
+		notifier.ID = primitive.NewObjectID()
+	}
+	if notifier.CreatedAt.IsZero() {
+		notifier.CreatedAt = time.Now()
+	}
+	notifier.UpdatedAt = time.Now()
+	filter := bson.M{"_id": notifier.ID}
+	update := bson.M{"$set": notifier}
+	opts := options.Update().SetUpsert(true)
+	_, err := NotifierCollection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+func GetNotifierByID(id primitive.ObjectID) (*model.Notifier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var notifier model.Notifier
+	err := NotifierCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&notifier)
+	if err != nil {
+		return nil, err
+	}
+	return &notifier, nil
+}
+
+func GetAllNotifiers() ([]*model.Notifier, error) {
+	var res []*model.Notifier
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := NotifierCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func GetNotifiersByOwner(owner string) ([]*model.Notifier, error) {
+	var res []*model.Notifier
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := NotifierCollection.Find(ctx, bson.M{"owner": owner})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func DeleteNotifierByID(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := NotifierCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
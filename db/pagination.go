@@ -0,0 +1,120 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page describes which slice of a list to return.
+// After is set when paging with a cursor, Offset is the fallback for limit/offset paging.
+type Page struct {
+	After  *Cursor
+	Offset int
+	Limit  int
+}
+
+// Cursor is the opaque position of the last returned item.
+// Keyset lists set Name and/or ID, lists without a stable key set Offset.
+type Cursor struct {
+	Name   string              `json:"n,omitempty"`
+	ID     *primitive.ObjectID `json:"i,omitempty"`
+	Offset int                 `json:"o,omitempty"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func NewPage(cursor string, offset int, limit int) (*Page, error) {
+	page := &Page{Offset: offset, Limit: limit}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+	if cursor == "" {
+		return page, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	page.After = &c
+	page.Offset = c.Offset
+	return page, nil
+}
+
+func (c *Cursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// skip is the number of documents to skip, keyset cursors already position the query
+func (p *Page) skip() int64 {
+	if p.After != nil && (p.After.Name != "" || p.After.ID != nil) {
+		return 0
+	}
+	return int64(p.Offset)
+}
+
+// offsetCursor returns the cursor for the next page of an offset based list,
+// or an empty string if the current page is the last one
+func (p *Page) offsetCursor(returned int, total int64) string {
+	next := int(p.skip()) + returned
+	if returned < p.Limit || int64(next) >= total {
+		return ""
+	}
+	return (&Cursor{Offset: next}).String()
+}
+
+// afterNameID matches documents sorted by (name, _id) after the cursor
+func (p *Page) afterNameID(field string) bson.M {
+	if p.After == nil || p.After.ID == nil {
+		return nil
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$gt": p.After.Name}},
+		bson.M{field: p.After.Name, "_id": bson.M{"$gt": *p.After.ID}},
+	}}
+}
+
+// afterID matches documents sorted by _id after the cursor
+func (p *Page) afterID() bson.M {
+	if p.After == nil || p.After.ID == nil {
+		return nil
+	}
+	return bson.M{"_id": bson.M{"$gt": *p.After.ID}}
+}
+
+func nameIDCursor(name string, id primitive.ObjectID) string {
+	return (&Cursor{Name: name, ID: &id}).String()
+}
+
+// PaginateSlice pages a list that is already held in memory, returning the next cursor.
+func PaginateSlice[T any](items []T, page *Page) ([]T, string) {
+	start := page.Offset
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + page.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+	next := ""
+	if end < len(items) {
+		next = (&Cursor{Offset: end}).String()
+	}
+	return items[start:end], next
+}
+
+// trimPage drops the extra item fetched to detect whether another page exists
+func trimPage[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
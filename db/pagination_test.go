@@ -0,0 +1,180 @@
+package db
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewPage(t *testing.T) {
+	id := primitive.NewObjectID()
+	tests := []struct {
+		name    string
+		cursor  string
+		offset  int
+		want    *Page
+		wantErr error
+	}{
+		{name: "first page", want: &Page{Limit: 10}},
+		{name: "offset", offset: 20, want: &Page{Offset: 20, Limit: 10}},
+		{name: "negative offset", offset: -5, want: &Page{Limit: 10}},
+		{name: "id cursor", cursor: (&Cursor{ID: &id}).String(), want: &Page{After: &Cursor{ID: &id}, Limit: 10}},
+		{name: "name cursor", cursor: nameIDCursor("Hades", id), want: &Page{After: &Cursor{Name: "Hades", ID: &id}, Limit: 10}},
+		{name: "offset cursor overrides offset", cursor: (&Cursor{Offset: 30}).String(), offset: 5, want: &Page{After: &Cursor{Offset: 30}, Offset: 30, Limit: 10}},
+		{name: "not base64", cursor: "!", wantErr: ErrInvalidCursor},
+		{name: "not json", cursor: "bm90IGpzb24", wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPage(tt.cursor, tt.offset, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageFilters(t *testing.T) {
+	id := primitive.NewObjectID()
+	tests := []struct {
+		name        string
+		page        *Page
+		skip        int64
+		afterID     bson.M
+		afterNameID bson.M
+	}{
+		{name: "first page", page: &Page{Limit: 10}},
+		{name: "offset", page: &Page{Offset: 20, Limit: 10}, skip: 20},
+		{name: "offset cursor", page: &Page{After: &Cursor{Offset: 30}, Offset: 30, Limit: 10}, skip: 30},
+		{
+			name:    "id cursor",
+			page:    &Page{After: &Cursor{ID: &id}, Limit: 10},
+			afterID: bson.M{"_id": bson.M{"$gt": id}},
+			afterNameID: bson.M{"$or": bson.A{
+				bson.M{"name": bson.M{"$gt": ""}},
+				bson.M{"name": "", "_id": bson.M{"$gt": id}},
+			}},
+		},
+		{
+			name:    "name cursor",
+			page:    &Page{After: &Cursor{Name: "Hades", ID: &id}, Limit: 10},
+			afterID: bson.M{"_id": bson.M{"$gt": id}},
+			afterNameID: bson.M{"$or": bson.A{
+				bson.M{"name": bson.M{"$gt": "Hades"}},
+				bson.M{"name": "Hades", "_id": bson.M{"$gt": id}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.skip(); got != tt.skip {
+				t.Errorf("skip = %d, want %d", got, tt.skip)
+			}
+			if got := tt.page.afterID(); !reflect.DeepEqual(got, tt.afterID) {
+				t.Errorf("afterID = %v, want %v", got, tt.afterID)
+			}
+			if got := tt.page.afterNameID("name"); !reflect.DeepEqual(got, tt.afterNameID) {
+				t.Errorf("afterNameID = %v, want %v", got, tt.afterNameID)
+			}
+		})
+	}
+}
+
+func TestOffsetCursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     *Page
+		returned int
+		total    int64
+		want     *Cursor
+	}{
+		{name: "more", page: &Page{Limit: 10}, returned: 10, total: 25, want: &Cursor{Offset: 10}},
+		{name: "from offset", page: &Page{Offset: 10, Limit: 10}, returned: 10, total: 25, want: &Cursor{Offset: 20}},
+		{name: "short page", page: &Page{Offset: 20, Limit: 10}, returned: 5, total: 25},
+		{name: "exactly the last page", page: &Page{Offset: 10, Limit: 10}, returned: 10, total: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := ""
+			if tt.want != nil {
+				want = tt.want.String()
+			}
+			if got := tt.page.offsetCursor(tt.returned, tt.total); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPaginateSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name string
+		page *Page
+		want []int
+		next *Cursor
+	}{
+		{name: "first page", page: &Page{Limit: 2}, want: []int{1, 2}, next: &Cursor{Offset: 2}},
+		{name: "middle", page: &Page{Offset: 2, Limit: 2}, want: []int{3, 4}, next: &Cursor{Offset: 4}},
+		{name: "last page", page: &Page{Offset: 4, Limit: 2}, want: []int{5}},
+		{name: "everything", page: &Page{Limit: 5}, want: []int{1, 2, 3, 4, 5}},
+		{name: "past the end", page: &Page{Offset: 9, Limit: 2}, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := PaginateSlice(items, tt.page)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			want := ""
+			if tt.next != nil {
+				want = tt.next.String()
+			}
+			if next != want {
+				t.Errorf("got next %q, want %q", next, want)
+			}
+		})
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []int
+		want  []int
+		more  bool
+	}{
+		{name: "extra item", items: []int{1, 2, 3}, want: []int{1, 2}, more: true},
+		{name: "full page", items: []int{1, 2}, want: []int{1, 2}},
+		{name: "short page", items: []int{1}, want: []int{1}},
+		{name: "empty", items: nil, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, more := trimPage(tt.items, 2)
+			if !slices.Equal(got, tt.want) || more != tt.more {
+				t.Errorf("got %v, %v, want %v, %v", got, more, tt.want, tt.more)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	for _, c := range []*Cursor{{ID: &id}, {Name: "Ведьмак 3", ID: &id}, {Offset: 40}} {
+		page, err := NewPage(c.String(), 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(page.After, c) {
+			t.Errorf("got %+v, want %+v", page.After, c)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// gameInfoFields maps the json field names of model.GameInfo to their bson names
+var gameInfoFields = map[string]string{
+	"id":             "_id",
+	"name":           "name",
+	"description":    "description",
+	"aliases":        "aliases",
+	"developers":     "developers",
+	"publishers":     "publishers",
+	"igdb_id":        "igdb_id",
+	"steam_id":       "steam_id",
+	"cover":          "cover",
+	"languages":      "languages",
+	"screenshots":    "screenshots",
+	"game_ids":       "games",
+	"game_downloads": "games",
+	"matches":        "matches",
+	"created_at":     "created_at",
+	"updated_at":     "updated_at",
+}
+
+// GameInfoViews are the named field sets, a nil set means every field
+var GameInfoViews = map[string][]string{
+	"summary": {"id", "name", "cover", "steam_id"},
+	"full":    nil,
+}
+
+// ParseGameInfoFields resolves the fields and view query parameters into json field names.
+// fields takes precedence over view, nil means every field. A fields list naming no field is an error.
+func ParseGameInfoFields(view string, fields string) ([]string, error) {
+	if fields != "" {
+		var res []string
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if _, ok := gameInfoFields[field]; !ok {
+				return nil, fmt.Errorf("unknown field: %s", field)
+			}
+			res = append(res, field)
+		}
+		if len(res) == 0 {
+			return nil, errors.New("fields must name at least one field")
+		}
+		return res, nil
+	}
+	if view == "" {
+		return nil, nil
+	}
+	res, ok := GameInfoViews[view]
+	if !ok {
+		return nil, fmt.Errorf("unknown view: %s", view)
+	}
+	return res, nil
+}
+
+// gameInfoProjection builds the MongoDB projection for json field names,
+// name is always kept since cursors are built from it
+func gameInfoProjection(fields []string) bson.M {
+	if fields == nil {
+		return nil
+	}
+	projection := bson.M{"_id": 1, "name": 1}
+	for _, field := range fields {
+		projection[gameInfoFields[field]] = 1
+	}
+	return projection
+}
+
+// findOneProjection returns the FindOne options loading the json field names
+func findOneProjection(fields []string) *options.FindOneOptions {
+	opts := options.FindOne()
+	if projection := gameInfoProjection(fields); projection != nil {
+		opts.SetProjection(projection)
+	}
+	return opts
+}
+
+// NeedGameItems reports whether the game items have to be loaded for the selected fields
+func NeedGameItems(fields []string) bool {
+	if fields == nil {
+		return true
+	}
+	for _, field := range fields {
+		if field == "game_downloads" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseGameInfoFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		view    string
+		fields  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "every field"},
+		{name: "view", view: "summary", want: GameInfoViews["summary"]},
+		{name: "full view", view: "full"},
+		{name: "unknown view", view: "compact", wantErr: true},
+		{name: "fields", fields: "name, cover,matches", want: []string{"name", "cover", "matches"}},
+		{name: "fields override view", view: "summary", fields: "description", want: []string{"description"}},
+		{name: "unknown field", fields: "name,secret", wantErr: true},
+		{name: "only commas", fields: ",", wantErr: true},
+		{name: "only spaces", fields: "  ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGameInfoFields(tt.view, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
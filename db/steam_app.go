@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	steamAppBatchSize = 1000
+	// words of more apps than this are only used when the name has no rarer word
+	steamAppCommonWord = 5000
+	// candidates sharing the most words with the name that are scored
+	steamAppMaxCandidates = 500
+)
+
+// steamAppIndex is a compact in-memory index of the Steam app list,
+// the list has a few hundred thousand apps, too many for a TrigramIndex
+type steamAppIndex struct {
+	ids        []int
+	names      []string
+	normalized []string
+	exact      map[string][]int32
+	words      map[string][]int32
+}
+
+var (
+	steamApps     *steamAppIndex
+	steamAppsMutx = &sync.RWMutex{}
+)
+
+type SteamAppMatch struct {
+	AppID int
+	Name  string
+	Score float64
+}
+
+// SaveSteamApps upserts apps, setting their updated_at to updatedAt
+func SaveSteamApps(apps []*model.SteamApp, updatedAt time.Time) error {
+	for start := 0; start < len(apps); start += steamAppBatchSize {
+		end := min(start+steamAppBatchSize, len(apps))
+		models := make([]mongo.WriteModel, 0, end-start)
+		for _, app := range apps[start:end] {
+			app.UpdatedAt = updatedAt
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": app.AppID}).
+				SetReplacement(app).
+				SetUpsert(true))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := SteamAppCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteSteamAppsUpdatedBefore deletes the apps that were not in the list since t
+func DeleteSteamAppsUpdatedBefore(t time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := SteamAppCollection.DeleteMany(ctx, bson.M{"updated_at": bson.M{"$lt": t}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// LoadSteamAppIndex builds the in-memory Steam app index from the database if it is not built yet
+func LoadSteamAppIndex() error {
+	steamAppsMutx.RLock()
+	loaded := steamApps != nil
+	steamAppsMutx.RUnlock()
+	if loaded {
+		return nil
+	}
+	return ReloadSteamAppIndex()
+}
+
+// ReloadSteamAppIndex rebuilds the in-memory Steam app index from the database
+func ReloadSteamAppIndex() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	opts := options.Find().SetProjection(bson.M{"name": 1})
+	cursor, err := SteamAppCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	idx := &steamAppIndex{
+		exact: make(map[string][]int32),
+		words: make(map[string][]int32),
+	}
+	for cursor.Next(ctx) {
+		var app model.SteamApp
+		if err := cursor.Decode(&app); err != nil {
+			return err
+		}
+		normalized := utils.NormalizeForIndex(app.Name)
+		if normalized == "" {
+			continue
+		}
+		i := int32(len(idx.ids))
+		idx.ids = append(idx.ids, app.AppID)
+		idx.names = append(idx.names, app.Name)
+		idx.normalized = append(idx.normalized, normalized)
+		idx.exact[normalized] = append(idx.exact[normalized], i)
+		for _, word := range uniqueWords(normalized) {
+			idx.words[word] = append(idx.words[word], i)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	steamAppsMutx.Lock()
+	steamApps = idx
+	steamAppsMutx.Unlock()
+	return nil
+}
+
+// CountSteamApps returns the number of apps in the in-memory index
+func CountSteamApps() (int, error) {
+	if err := LoadSteamAppIndex(); err != nil {
+		return 0, err
+	}
+	steamAppsMutx.RLock()
+	defer steamAppsMutx.RUnlock()
+	return len(steamApps.ids), nil
+}
+
+// FindSteamAppsByName returns the apps whose normalized name equals the normalized name
+func FindSteamAppsByName(name string) ([]SteamAppMatch, error) {
+	if err := LoadSteamAppIndex(); err != nil {
+		return nil, err
+	}
+	steamAppsMutx.RLock()
+	defer steamAppsMutx.RUnlock()
+	var res []SteamAppMatch
+	for _, i := range steamApps.exact[utils.NormalizeForIndex(name)] {
+		res = append(res, SteamAppMatch{AppID: steamApps.ids[i], Name: steamApps.names[i], Score: 1})
+	}
+	return res, nil
+}
+
+// FuzzySearchSteamApps returns up to limit apps scoring at least minScore against name
+// with utils.Similarity, best match first
+func FuzzySearchSteamApps(name string, limit int, minScore float64) ([]SteamAppMatch, error) {
+	if err := LoadSteamAppIndex(); err != nil {
+		return nil, err
+	}
+	normalized := utils.NormalizeForIndex(name)
+	if normalized == "" || limit <= 0 {
+		return nil, nil
+	}
+	steamAppsMutx.RLock()
+	defer steamAppsMutx.RUnlock()
+	words := uniqueWords(normalized)
+	rare := make([]string, 0, len(words))
+	for _, word := range words {
+		if len(steamApps.words[word]) <= steamAppCommonWord {
+			rare = append(rare, word)
+		}
+	}
+	if len(rare) > 0 {
+		words = rare
+	}
+	hits := make(map[int32]int)
+	for _, word := range words {
+		for _, i := range steamApps.words[word] {
+			hits[i]++
+		}
+	}
+	candidates := make([]int32, 0, len(hits))
+	for i := range hits {
+		candidates = append(candidates, i)
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		if hits[candidates[a]] != hits[candidates[b]] {
+			return hits[candidates[a]] > hits[candidates[b]]
+		}
+		return candidates[a] < candidates[b]
+	})
+	if len(candidates) > steamAppMaxCandidates {
+		candidates = candidates[:steamAppMaxCandidates]
+	}
+	var res []SteamAppMatch
+	for _, i := range candidates {
+		score := utils.Similarity(normalized, steamApps.normalized[i])
+		if score >= minScore {
+			res = append(res, SteamAppMatch{AppID: steamApps.ids[i], Name: steamApps.names[i], Score: score})
+		}
+	}
+	sort.SliceStable(res, func(a, b int) bool {
+		return res[a].Score > res[b].Score
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func uniqueWords(normalized string) []string {
+	words := strings.Fields(normalized)
+	return utils.Unique(words)
+}
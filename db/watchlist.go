@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func SaveWatchlistEntry(entry *model.WatchlistEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	filter := bson.M{"_id": entry.ID}
+	update := bson.M{"$set": entry}
+	opts := options.Update().SetUpsert(true)
+	_, err := WatchlistCollection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+func GetWatchlistByOwner(owner string) ([]*model.WatchlistEntry, error) {
+	var res []*model.WatchlistEntry
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	cursor, err := WatchlistCollection.Find(ctx, bson.M{"owner": owner}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func GetAllWatchlistEntries() ([]*model.WatchlistEntry, error) {
+	var res []*model.WatchlistEntry
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := WatchlistCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteWatchlistEntry deletes an entry of the owner, mongo.ErrNoDocuments is returned if the owner has no such entry
+func DeleteWatchlistEntry(owner string, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := WatchlistCollection.DeleteOne(ctx, bson.M{"_id": id, "owner": owner})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func SetWatchlistEntryNotified(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := WatchlistCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_notified_at": time.Now()}})
+	return err
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func SaveWebhook(hook *model.Webhook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if hook.ID.IsZero() {
+		hook.ID = primitive.NewObjectID()
+	}
+	if hook.CreatedAt.IsZero() {
+		hook.CreatedAt = time.Now()
+	}
+	hook.UpdatedAt = time.Now()
+	filter := bson.M{"_id": hook.ID}
+	update := bson.M{"$set": hook}
+	opts := options.Update().SetUpsert(true)
+	_, err := WebhookCollection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+func GetWebhookByID(id primitive.ObjectID) (*model.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var hook model.Webhook
+	err := WebhookCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&hook)
+	if err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func GetAllWebhooks() ([]*model.Webhook, error) {
+	var res []*model.Webhook
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := WebhookCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteWebhookByID deletes the webhook and its delivery log
+func DeleteWebhookByID(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := WebhookCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	_, err = WebhookDeliveryCollection.DeleteMany(ctx, bson.M{"webhook_id": id})
+	return err
+}
+
+func SaveWebhookDelivery(delivery *model.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	delivery.UpdatedAt = time.Now()
+	filter := bson.M{"_id": delivery.ID}
+	update := bson.M{"$set": delivery}
+	opts := options.Update().SetUpsert(true)
+	_, err := WebhookDeliveryCollection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+func GetWebhookDeliveryByID(id primitive.ObjectID) (*model.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var delivery model.WebhookDelivery
+	err := WebhookDeliveryCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&delivery)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetWebhookDeliveriesPage returns the delivery log of a webhook, newest first
+func GetWebhookDeliveriesPage(webhookID primitive.ObjectID, page *Page) ([]*model.WebhookDelivery, string, int64, error) {
+	var res []*model.WebhookDelivery
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.M{"webhook_id": webhookID}
+	total, err := WebhookDeliveryCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if page.After != nil && page.After.ID != nil {
+		filter["_id"] = bson.M{"$lt": *page.After.ID}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(page.skip()).
+		SetLimit(int64(page.Limit + 1))
+	cursor, err := WebhookDeliveryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, "", 0, err
+	}
+	res, more := trimPage(res, page.Limit)
+	next := ""
+	if more {
+		id := res[len(res)-1].ID
+		next = (&Cursor{ID: &id}).String()
+	}
+	return res, next, total, nil
+}
+
+// GetDueWebhookDeliveries returns pending deliveries whose next attempt is due
+func GetDueWebhookDeliveries(limit int) ([]*model.WebhookDelivery, error) {
+	var res []*model.WebhookDelivery
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.M{
+		"status":          model.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": time.Now()},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := WebhookDeliveryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClaimWebhookDelivery reserves a due pending delivery for lease, so that only one worker attempts it.
+// It returns mongo.ErrNoDocuments if the delivery is not due or already claimed.
+func ClaimWebhookDelivery(id primitive.ObjectID, lease time.Duration) (*model.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	now := time.Now()
+	filter := bson.M{
+		"_id":             id,
+		"status":          model.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var delivery model.WebhookDelivery
+	err := WebhookDeliveryCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,169 @@
+// Package events is an in-process event bus for crawl and organize events.
+// When Redis is available, events are relayed between pcgamedb processes,
+// so a crawl started from the command line reaches the server's subscribers.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Type string
+
+const (
+	ItemCreated   Type = "item-created"
+	ItemUpdated   Type = "item-updated"
+	InfoOrganized Type = "info-organized"
+	CrawlStarted  Type = "crawl-started"
+	CrawlFinished Type = "crawl-finished"
+	SourceFailed  Type = "source-failed"
+	CleanFinished Type = "clean-finished"
+)
+
+var Types = []Type{ItemCreated, ItemUpdated, InfoOrganized, CrawlStarted, CrawlFinished, SourceFailed, CleanFinished}
+
+type Event struct {
+	ID   uint64    `json:"id"`
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+	// Remote is set on events relayed from another process
+	Remote bool `json:"-"`
+}
+
+// InfoOrganizedData is published when game items are linked to a game info
+type InfoOrganizedData struct {
+	Info    *model.GameInfo      `json:"info"`
+	GameIDs []primitive.ObjectID `json:"game_ids"`
+}
+
+type CrawlStartedData struct {
+	Crawlers []string `json:"crawlers"`
+}
+
+type CrawlFinishedData struct {
+	Count    int      `json:"count"`
+	Failed   []string `json:"failed,omitempty"`
+	Duration string   `json:"duration"`
+}
+
+// SourceFailedData is published when a crawler fails, Source is the crawler name (e.g. fitgirl)
+type SourceFailedData struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// CleanFinishedData is the report of a database clean
+type CleanFinishedData struct {
+	Deduplicated int      `json:"deduplicated"`
+	OrphanGames  int      `json:"orphan_games"`
+	EmptyInfos   int      `json:"empty_infos"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// Hook is called synchronously for every event published by this process
+type Hook func(e *Event)
+
+const (
+	historySize      = 256
+	subscriberBuffer = 64
+)
+
+type Subscription struct {
+	C     <-chan *Event
+	c     chan *Event
+	types map[Type]bool
+}
+
+type bus struct {
+	mu          sync.RWMutex
+	hooks       []Hook
+	lastID      uint64
+	history     []*Event
+	subscribers map[*Subscription]struct{}
+}
+
+var defaultBus = &bus{subscribers: make(map[*Subscription]struct{})}
+
+// Publish sends an event to every local subscriber and to the other processes
+func Publish(t Type, data any) {
+	e := &Event{Type: t, Time: time.Now(), Data: data}
+	defaultBus.publish(e)
+	defaultBus.mu.RLock()
+	hooks := defaultBus.hooks
+	defaultBus.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(e)
+	}
+	relay(e)
+}
+
+// AddHook registers a hook for the events published by this process, relayed events are not passed to hooks
+func AddHook(hook Hook) {
+	defaultBus.mu.Lock()
+	defer defaultBus.mu.Unlock()
+	defaultBus.hooks = append(defaultBus.hooks, hook)
+}
+
+// Subscribe returns a subscription to the given event types, all types if none are given.
+// Events published after lastID that are still in the history are delivered first.
+// Slow subscribers miss events instead of blocking the publisher.
+func Subscribe(lastID uint64, types ...Type) *Subscription {
+	return defaultBus.subscribe(lastID, types)
+}
+
+func Unsubscribe(s *Subscription) {
+	defaultBus.mu.Lock()
+	defer defaultBus.mu.Unlock()
+	if _, ok := defaultBus.subscribers[s]; ok {
+		delete(defaultBus.subscribers, s)
+		close(s.c)
+	}
+}
+
+func (s *Subscription) wants(t Type) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+func (b *bus) publish(e *Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	e.ID = b.lastID
+	b.history = append(b.history, e)
+	if len(b.history) > historySize {
+		b.history = b.history[len(b.history)-historySize:]
+	}
+	for s := range b.subscribers {
+		if !s.wants(e.Type) {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+		}
+	}
+}
+
+func (b *bus) subscribe(lastID uint64, types []Type) *Subscription {
+	c := make(chan *Event, subscriberBuffer+historySize)
+	s := &Subscription{C: c, c: c, types: make(map[Type]bool)}
+	for _, t := range types {
+		s.types[t] = true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lastID > 0 {
+		for _, e := range b.history {
+			if e.ID > lastID && s.wants(e.Type) {
+				c <- e
+			}
+		}
+	}
+	b.subscribers[s] = struct{}{}
+	return s
+}
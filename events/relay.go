@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nitezs/pcgamedb/cache"
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const relayChannel = "pcgamedb:events"
+
+// instanceID tells the events relayed by this process apart from the ones of other processes
+var instanceID = primitive.NewObjectID().Hex()
+
+type relayMessage struct {
+	Origin string `json:"origin"`
+	Event  *Event `json:"event"`
+}
+
+type relayedEvent struct {
+	Origin string `json:"origin"`
+	Event  struct {
+		Type Type            `json:"type"`
+		Time time.Time       `json:"time"`
+		Data json.RawMessage `json:"data"`
+	} `json:"event"`
+}
+
+func relay(e *Event) {
+	if !config.Config.RedisAvaliable {
+		return
+	}
+	data, err := json.Marshal(relayMessage{Origin: instanceID, Event: e})
+	if err != nil {
+		log.Logger.Warn("Failed to encode event", zap.String("type", string(e.Type)), zap.Error(err))
+		return
+	}
+	if err := cache.Publish(relayChannel, data); err != nil {
+		log.Logger.Warn("Failed to relay event", zap.String("type", string(e.Type)), zap.Error(err))
+	}
+}
+
+// ListenRelay republishes the events relayed by other processes to the local subscribers until ctx is done
+func ListenRelay(ctx context.Context) {
+	if !config.Config.RedisAvaliable {
+		return
+	}
+	for msg := range cache.Subscribe(ctx, relayChannel) {
+		var relayed relayedEvent
+		if err := json.Unmarshal([]byte(msg), &relayed); err != nil {
+			log.Logger.Warn("Failed to decode relayed event", zap.Error(err))
+			continue
+		}
+		if relayed.Origin == instanceID {
+			continue
+		}
+		defaultBus.publish(&Event{Type: relayed.Event.Type, Time: relayed.Event.Time, Data: relayed.Event.Data, Remote: true})
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ScopeRead     = "read"
+	ScopeOrganize = "organize"
+	ScopeDelete   = "delete"
+	ScopeClean    = "clean"
+	// ScopeNotify allows to change notifiers and watchlists, which make the server send requests
+	ScopeNotify = "notify"
+	// ScopeAdmin grants every other scope
+	ScopeAdmin = "admin"
+)
+
+// AdminOwner owns the resources created with the server secret key,
+// API keys cannot be created for it
+const AdminOwner = "admin"
+
+var Scopes = []string{ScopeRead, ScopeOrganize, ScopeDelete, ScopeClean, ScopeNotify, ScopeAdmin}
+
+// APIKey is stored without the key, Hash is the hex SHA-256 of the key
+// and Prefix its first characters, to tell keys apart
+type APIKey struct {
+	ID     primitive.ObjectID `json:"id" bson:"_id"`
+	Owner  string             `json:"owner" bson:"owner"`
+	Name   string             `json:"name" bson:"name"`
+	Prefix string             `json:"prefix" bson:"prefix"`
+	Hash   string             `json:"-" bson:"hash"`
+	Scopes []string           `json:"scopes" bson:"scopes"`
+	// DailyQuota is the number of requests to authenticated routes allowed per UTC day, 0 for no limit.
+	// Public routes do not count, even when the key is sent.
+	DailyQuota int        `json:"daily_quota" bson:"daily_quota"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// HasScopes reports whether the key grants every given scope
+func (k *APIKey) HasScopes(scopes ...string) bool {
+	if slices.Contains(k.Scopes, ScopeAdmin) {
+		return true
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(k.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func (k *APIKey) Expired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
@@ -21,6 +21,7 @@ type GameInfo struct {
 	Screenshots []string             `json:"screenshots" bson:"screenshots"`
 	GameIDs     []primitive.ObjectID `json:"game_ids" bson:"games"`
 	Games       []*GameItem          `json:"game_downloads" bson:"-"`
+	Matches     []GameMatch          `json:"matches,omitempty" bson:"matches,omitempty"`
 	CreatedAt   time.Time            `json:"created_at" bson:"created_at"`
 	UpdatedAt   time.Time            `json:"updated_at" bson:"updated_at"`
 }
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MatchReviewPending  = "pending"
+	MatchReviewAccepted = "accepted"
+	MatchReviewRejected = "rejected"
+)
+
+// Reasons a game item is in the review queue
+const (
+	ReviewReasonLowConfidence = "low_confidence"
+	ReviewReasonNotFound      = "not_found"
+	ReviewReasonAmbiguous     = "ambiguous"
+)
+
+// MatchCandidate is a game of a platform scored against the name of a game item.
+// Confidence is between 0 and 1, Reasons tell what raised or lowered it.
+type MatchCandidate struct {
+	Platform   string   `json:"platform" bson:"platform"`
+	PlatformID int      `json:"platform_id" bson:"platform_id"`
+	Name       string   `json:"name" bson:"name"`
+	Year       int      `json:"year,omitempty" bson:"year,omitempty"`
+	Developers []string `json:"developers,omitempty" bson:"developers,omitempty"`
+	Confidence float64  `json:"confidence" bson:"confidence"`
+	Reasons    []string `json:"reasons,omitempty" bson:"reasons,omitempty"`
+}
+
+// GameMatch records why a game item was linked to a game info
+type GameMatch struct {
+	GameID    primitive.ObjectID `json:"game_id" bson:"game_id"`
+	Chosen    MatchCandidate     `json:"chosen" bson:"chosen"`
+	RunnerUps []MatchCandidate   `json:"runner_ups,omitempty" bson:"runner_ups,omitempty"`
+	MatchedAt time.Time          `json:"matched_at" bson:"matched_at"`
+}
+
+// MatchReview is a game item that could not be linked automatically, because its best match
+// was not confident enough or no match was found. Candidates are best first.
+type MatchReview struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	GameID     primitive.ObjectID `json:"game_id" bson:"game_id"`
+	Game       *GameItem          `json:"game,omitempty" bson:"-"`
+	Name       string             `json:"name" bson:"name"`
+	RawName    string             `json:"raw_name" bson:"raw_name"`
+	Author     string             `json:"author" bson:"author"`
+	Reason     string             `json:"reason" bson:"reason"`
+	Error      string             `json:"error,omitempty" bson:"error,omitempty"`
+	Candidates []MatchCandidate   `json:"candidates" bson:"candidates"`
+	Status     string             `json:"status" bson:"status"`
+	ReviewedBy string             `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+}
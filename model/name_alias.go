@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// NameAlias links a normalized game name to the game it was manually organized to.
+// GameName is the name the alias was learned from, it is normalized again on import.
+type NameAlias struct {
+	Name       string    `json:"name" bson:"_id"`
+	GameName   string    `json:"game_name" bson:"game_name"`
+	Platform   string    `json:"platform" bson:"platform"`
+	PlatformID int       `json:"platform_id" bson:"platform_id"`
+	Source     string    `json:"source" bson:"source"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+}
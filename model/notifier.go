@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NotifierTelegram = "telegram"
+	NotifierDiscord  = "discord"
+	NotifierMatrix   = "matrix"
+)
+
+// Notifier is a chat channel that is told about new and updated game items.
+// Only the settings of its Type are used.
+type Notifier struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id"`
+	Owner    string             `json:"owner" bson:"owner"`
+	Name     string             `json:"name" bson:"name"`
+	Type     string             `json:"type" bson:"type"`
+	Telegram *TelegramSettings  `json:"telegram,omitempty" bson:"telegram,omitempty"`
+	Discord  *DiscordSettings   `json:"discord,omitempty" bson:"discord,omitempty"`
+	Matrix   *MatrixSettings    `json:"matrix,omitempty" bson:"matrix,omitempty"`
+	// Authors limits the notifications to items of these authors, all authors when empty
+	Authors []string `json:"authors" bson:"authors"`
+	// GameInfoIDs limits the notifications to these games, all games when empty
+	GameInfoIDs []primitive.ObjectID `json:"game_info_ids" bson:"game_info_ids"`
+	// WatchlistOnly notifiers are only told about the items of the watchlist entries pointing at them
+	WatchlistOnly bool      `json:"watchlist_only" bson:"watchlist_only"`
+	Active        bool      `json:"active" bson:"active"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type TelegramSettings struct {
+	BotToken string `json:"bot_token,omitempty" bson:"bot_token"`
+	ChatID   string `json:"chat_id" bson:"chat_id"`
+}
+
+type DiscordSettings struct {
+	WebhookURL string `json:"webhook_url,omitempty" bson:"webhook_url"`
+}
+
+type MatrixSettings struct {
+	Homeserver  string `json:"homeserver" bson:"homeserver"`
+	AccessToken string `json:"access_token,omitempty" bson:"access_token"`
+	RoomID      string `json:"room_id" bson:"room_id"`
+}
+
+// Redact clears the credentials before the notifier is returned by the API
+func (n *Notifier) Redact() {
+	if n.Telegram != nil {
+		n.Telegram.BotToken = ""
+	}
+	if n.Discord != nil {
+		n.Discord.WebhookURL = ""
+	}
+	if n.Matrix != nil {
+		n.Matrix.AccessToken = ""
+	}
+}
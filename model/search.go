@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type SearchFilter struct {
+	Author       string    `json:"author,omitempty"`
+	Developer    string    `json:"developer,omitempty"`
+	Publisher    string    `json:"publisher,omitempty"`
+	Language     string    `json:"language,omitempty"`
+	HasSteamID   *bool     `json:"has_steam_id,omitempty"`
+	MinSize      int64     `json:"min_size,omitempty"`
+	MaxSize      int64     `json:"max_size,omitempty"`
+	UpdatedSince time.Time `json:"updated_since,omitempty"`
+}
+
+func (f *SearchFilter) IsEmpty() bool {
+	if f == nil {
+		return true
+	}
+	return f.Author == "" &&
+		f.Developer == "" &&
+		f.Publisher == "" &&
+		f.Language == "" &&
+		f.HasSteamID == nil &&
+		f.MinSize == 0 &&
+		f.MaxSize == 0 &&
+		f.UpdatedSince.IsZero()
+}
+
+type FacetCount struct {
+	Value string `json:"value" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
+
+type SearchFacets struct {
+	Authors    []FacetCount `json:"authors" bson:"authors"`
+	Developers []FacetCount `json:"developers" bson:"developers"`
+	Publishers []FacetCount `json:"publishers" bson:"publishers"`
+	Languages  []FacetCount `json:"languages" bson:"languages"`
+	HasSteamID []FacetCount `json:"has_steam_id" bson:"has_steam_id"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// SteamApp is an entry of the local copy of the Steam app list
+type SteamApp struct {
+	AppID     int       `json:"appid" bson:"_id"`
+	Name      string    `json:"name" bson:"name"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
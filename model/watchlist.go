@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WatchlistEntry is the interest of a user in a game, exactly one of
+// SteamID, IGDBID, GameInfoID and Name is set
+type WatchlistEntry struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	Owner      string             `json:"owner" bson:"owner"`
+	SteamID    int                `json:"steam_id,omitempty" bson:"steam_id,omitempty"`
+	IGDBID     int                `json:"igdb_id,omitempty" bson:"igdb_id,omitempty"`
+	GameInfoID primitive.ObjectID `json:"game_info_id,omitempty" bson:"game_info_id,omitempty"`
+	// Name matches game infos whose name or alias contains it
+	Name string `json:"name,omitempty" bson:"name,omitempty"`
+	// Authors limits the entry to items of these authors, all authors when empty
+	Authors []string `json:"authors" bson:"authors"`
+	// NotifierID is the notifier told about the matching items
+	NotifierID     primitive.ObjectID `json:"notifier_id" bson:"notifier_id"`
+	LastNotifiedAt time.Time          `json:"last_notified_at,omitempty" bson:"last_notified_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Webhook struct {
+	ID  primitive.ObjectID `json:"id" bson:"_id"`
+	URL string             `json:"url" bson:"url"`
+	// Secret signs the deliveries, it is only returned when the webhook is created
+	Secret    string    `json:"secret,omitempty" bson:"secret"`
+	Events    []string  `json:"events" bson:"events"`
+	Authors   []string  `json:"authors" bson:"authors"`
+	Active    bool      `json:"active" bson:"active"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+const (
+	WebhookDeliveryPending = "pending"
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailed  = "failed"
+)
+
+type WebhookDelivery struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	WebhookID     primitive.ObjectID `json:"webhook_id" bson:"webhook_id"`
+	Event         string             `json:"event" bson:"event"`
+	Payload       string             `json:"payload" bson:"payload"`
+	Status        string             `json:"status" bson:"status"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	ResponseCode  int                `json:"response_code,omitempty" bson:"response_code"`
+	Error         string             `json:"error,omitempty" bson:"error"`
+	NextAttemptAt time.Time          `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+}
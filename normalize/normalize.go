@@ -0,0 +1,84 @@
+// Package normalize turns the raw names of game items into game names by chains of named rules.
+// Each source has its own chain, built from the rules shared by all sources.
+package normalize
+
+import (
+	"sort"
+	"strings"
+)
+
+// Rule is one named step of a Normalizer
+type Rule struct {
+	Name        string
+	Description string
+	apply       func(string) string
+}
+
+// NewRule returns a rule applying fn
+func NewRule(name string, description string, fn func(string) string) Rule {
+	return Rule{Name: name, Description: description, apply: fn}
+}
+
+func (r Rule) Apply(name string) string {
+	return r.apply(name)
+}
+
+// Step is a rule that changed a name
+type Step struct {
+	Rule   string `json:"rule"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Normalizer applies its rules in order
+type Normalizer struct {
+	Name  string
+	Rules []Rule
+}
+
+func New(name string, rules ...Rule) *Normalizer {
+	return &Normalizer{Name: name, Rules: rules}
+}
+
+func (n *Normalizer) Normalize(name string) string {
+	for _, rule := range n.Rules {
+		name = rule.Apply(name)
+	}
+	return name
+}
+
+// Explain normalizes name and returns the rules that changed it
+func (n *Normalizer) Explain(name string) (string, []Step) {
+	var steps []Step
+	for _, rule := range n.Rules {
+		after := rule.Apply(name)
+		if after != name {
+			steps = append(steps, Step{Rule: rule.Name, Before: name, After: after})
+		}
+		name = after
+	}
+	return name, steps
+}
+
+var sources = map[string]*Normalizer{}
+
+func register(n *Normalizer) *Normalizer {
+	sources[n.Name] = n
+	return n
+}
+
+// For returns the normalizer of a source, the name is case insensitive
+func For(source string) (*Normalizer, bool) {
+	n, ok := sources[strings.ToLower(source)]
+	return n, ok
+}
+
+// Sources returns the names of the sources with a normalizer
+func Sources() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
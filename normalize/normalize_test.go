@@ -0,0 +1,138 @@
+package normalize
+
+import (
+	"slices"
+	"testing"
+)
+
+// corpusCase is a raw name of a source, the name it normalizes to and the rules that change it on the way
+type corpusCase struct {
+	source string
+	raw    string
+	want   string
+	rules  []string
+}
+
+// corpus holds real raw names of every source. Add the names a rule change is meant to fix.
+var corpus = []corpusCase{
+	// generic
+	{source: "generic", raw: "The Witcher 3: Wild Hunt GOTY", want: "The Witcher 3: Wild Hunt", rules: []string{"strip-goty", "trim"}},
+	{source: "generic", raw: "Cyberpunk 2077: Ultimate Edition", want: "Cyberpunk 2077", rules: []string{"strip-edition-words", "collapse-spaces", "trim"}},
+	{source: "generic", raw: "Crash Bandicoot N. Sane Trilogy NSW for PC", want: "Crash Bandicoot N. Sane Trilogy", rules: []string{"strip-platform-tag", "trim"}},
+	{source: "generic", raw: "Age of Empires II: Definitive Edition", want: "Age of Empires II", rules: []string{"strip-edition-words", "collapse-spaces", "trim"}},
+	{source: "generic", raw: "Command & Conquer: Remastered Collection", want: "Command & Conquer", rules: []string{"strip-edition-words", "collapse-spaces", "trim"}},
+	{source: "generic", raw: "Dark Souls: Remastered", want: "Dark Souls", rules: []string{"strip-remaster-suffix"}},
+	{source: "generic", raw: "Hades", want: "Hades"},
+	{source: "generic", raw: "Mass Effect Legendary Edition (2021)", want: "Mass Effect", rules: []string{"strip-edition-words", "strip-parenthesized", "collapse-spaces", "trim"}},
+	{source: "generic", raw: "Baldur's Gate 3 - Digital Deluxe", want: "Baldur's Gate 3", rules: []string{"strip-edition-words", "collapse-spaces", "trim"}},
+	{source: "generic", raw: "Doom (2016)", want: "Doom", rules: []string{"strip-parenthesized", "trim"}},
+
+	// dodi
+	{source: "dodi", raw: "Cyberpunk 2077 - Ultimate Edition (v2.12 + All DLCs + Bonus Content, MULTi19) - [DODI Repack]", want: "Cyberpunk 2077", rules: []string{"strip-repack-tag", "strip-bonus-content", "strip-bracketed-metadata", "strip-edition-suffix", "trim"}},
+	{source: "dodi", raw: "Hogwarts Legacy: Digital Deluxe Edition (v1121020 + All DLCs + MULTi14) - [DODI Repack]", want: "Hogwarts Legacy: Digital Deluxe Edition", rules: []string{"strip-repack-tag", "strip-bonus-content", "strip-bracketed-metadata", "trim"}},
+	{source: "dodi", raw: "Red Dead Redemption 2 - Ultimate Edition (Build 1491.50 + All DLCs + Bonus Content, MULTi13) - [DODI Repack]", want: "Red Dead Redemption 2", rules: []string{"strip-repack-tag", "strip-bonus-content", "strip-bracketed-metadata", "strip-edition-suffix", "trim"}},
+	{source: "dodi", raw: "Assassin's Creed Mirage – Deluxe Edition (v1.0.7 + All DLCs) - [DODI Repack]", want: "Assassin's Creed Mirage", rules: []string{"strip-repack-tag", "strip-bonus-content", "strip-dash-suffix", "trim"}},
+	{source: "dodi", raw: "Ведьмак 3: Дикая Охота / The Witcher 3: Wild Hunt (v4.04) - [DODI Repack]", want: "The Witcher 3: Wild Hunt", rules: []string{"strip-repack-tag", "strip-bracketed-metadata", "pick-latin-title", "trim"}},
+	{source: "dodi", raw: "Halo: The Master Chief Collection - [DODI Repack]", want: "Halo: The Master Chief Collection", rules: []string{"strip-repack-tag"}},
+	{source: "dodi", raw: "Need for Speed: Hot Pursuit - Remastered (v1.0.5 + MULTi8) - [DODI Repack]", want: "Need for Speed: Hot Pursuit", rules: []string{"strip-repack-tag", "strip-remaster-suffix", "strip-bonus-content", "strip-bracketed-metadata", "trim"}},
+	{source: "dodi", raw: "Resident Evil Village - Gold Edition [DODI Repack]", want: "Resident Evil Village", rules: []string{"strip-repack-tag", "strip-edition-suffix", "trim"}},
+	{source: "dodi", raw: "Call of Duty: Black Ops III - AiO (All DLCs) - [DODI Repack]", want: "Call of Duty: Black Ops III", rules: []string{"strip-repack-tag", "strip-bracketed-metadata", "strip-all-in-one", "trim"}},
+	{source: "dodi", raw: "The Sims 4 - Portable (v1.105) - [DODI Repack]", want: "The Sims 4", rules: []string{"strip-repack-tag", "strip-bracketed-metadata", "strip-portable", "trim"}},
+	{source: "dodi", raw: "Lords of the Fallen  (v1.5) - [DODI Repack]", want: "Lords of the Fallen", rules: []string{"strip-repack-tag", "strip-bracketed-metadata", "collapse-spaces", "trim"}},
+
+	// kaoskrew
+	{source: "kaoskrew", raw: "Lies.of.P.v1.5.0.0.MULTi13.REPACK-KaOs", want: "Lies of P", rules: []string{"strip-version-tag", "dots-to-spaces", "trim"}},
+	{source: "kaoskrew", raw: "Palworld.v0.1.5.1.MULTi15.REPACK-KaOs", want: "Palworld", rules: []string{"strip-version-tag", "dots-to-spaces", "trim"}},
+	{source: "kaoskrew", raw: "Hades.II.Build.14568052.REPACK-KaOs", want: "Hades II", rules: []string{"strip-version-tag", "dots-to-spaces", "trim"}},
+	{source: "kaoskrew", raw: "The.Witcher.3.Wild.Hunt.GOTY.v4.04.MULTi15.REPACK2-KaOs", want: "The Witcher 3 Wild Hunt", rules: []string{"strip-version-tag", "dots-to-spaces", "strip-goty", "collapse-spaces", "trim"}},
+	{source: "kaoskrew", raw: "Sons.Of.The.Forest.v40215.UPDATE-KaOs", want: "Sons Of The Forest", rules: []string{"strip-version-tag", "dots-to-spaces", "trim"}},
+	{source: "kaoskrew", raw: "Mafia.Definitive.Edition.MULTi12.REPACK-KaOs", want: "Mafia Definitive Edition", rules: []string{"strip-repack-tag", "strip-language-tag", "dots-to-spaces"}},
+
+	// freegog
+	{source: "freegog", raw: "Stardew Valley v1.6.8 (GOG)", want: "Stardew Valley", rules: []string{"strip-parenthesized", "strip-version-tag", "trim"}},
+	{source: "freegog", raw: "Baldur's Gate 3 (v4.1.1.5022896 + Digital Deluxe Edition) (GOG)", want: "Baldur's Gate 3", rules: []string{"strip-parenthesized", "collapse-spaces", "trim"}},
+	{source: "freegog", raw: "The Witcher 3: Wild Hunt: GOTY v4.04a_redkit (GOG)", want: "The Witcher 3: Wild Hunt: Game Of The Year", rules: []string{"strip-parenthesized", "strip-version-tag", "expand-goty", "trim"}},
+	{source: "freegog", raw: "Disco Elysium: The Final Cut + Soundtrack", want: "Disco Elysium: The Final Cut", rules: []string{"strip-bonus-content", "trim"}},
+	{source: "freegog", raw: "Cyberpunk 2077 (GOG) v2.12", want: "Cyberpunk 2077", rules: []string{"strip-parenthesized", "strip-version-tag", "collapse-spaces", "trim"}},
+
+	// xatab
+	{source: "xatab", raw: "Ведьмак 3: Дикая Охота / The Witcher 3: Wild Hunt [v 4.04] (2015) PC | RePack от xatab", want: "The Witcher 3: Wild Hunt", rules: []string{"strip-version-tag", "strip-bracketed-metadata", "pick-latin-title", "trim"}},
+	{source: "xatab", raw: "Metro Exodus - Gold Edition [v 1.0.8.39 + DLCs] (2019) PC | RePack от xatab", want: "Metro Exodus - Gold Edition", rules: []string{"strip-version-tag", "strip-bracketed-metadata", "trim"}},
+	{source: "xatab", raw: "Far Cry 5: Gold Edition [v 1.011 + DLCs] (2018) PC | RePack от xatab", want: "Far Cry 5: Gold Edition", rules: []string{"strip-version-tag", "strip-bracketed-metadata", "trim"}},
+	{source: "xatab", raw: "Hitman 3 (2021) PC | RePack от xatab", want: "Hitman 3", rules: []string{"strip-bracketed-metadata", "trim"}},
+	{source: "xatab", raw: "Forever Skies (2023) PC", want: "Forever Skies", rules: []string{"strip-bracketed-metadata", "trim"}},
+	{source: "xatab", raw: "Ведьмак 3 / Дикая Охота (2015)", want: "Vedmak 3 / Dikaya Okhota", rules: []string{"strip-bracketed-metadata", "transliterate-cyrillic", "trim"}},
+	{source: "xatab", raw: "Sekiro: Shadows Die Twice PC", want: "Sekiro: Shadows Die Twice", rules: []string{"strip-platform-tag"}},
+	{source: "xatab", raw: "DOOM Eternal {v 6.66} PC", want: "DOOM Eternal", rules: []string{"strip-version-tag", "strip-bracketed-metadata", "trim"}},
+	{source: "xatab", raw: "Forever Skies 2 (2024) PC | RePack от xatab", want: "Forever Skies 2", rules: []string{"strip-bracketed-metadata", "trim"}},
+
+	// onlinefix
+	{source: "onlinefix", raw: "Elden Ring по сети", want: "Elden Ring", rules: []string{"strip-repack-tag", "trim"}},
+	{source: "onlinefix", raw: "Lethal Company по сети (Steam)", want: "Lethal Company", rules: []string{"strip-repack-tag", "strip-parenthesized", "collapse-spaces", "trim"}},
+	{source: "onlinefix", raw: "Ведьмак 3 по сети", want: "Vedmak 3", rules: []string{"strip-repack-tag", "transliterate-cyrillic", "trim"}},
+	{source: "onlinefix", raw: "Palworld (Xbox) по сети", want: "Palworld", rules: []string{"strip-repack-tag", "strip-parenthesized", "collapse-spaces", "trim"}},
+
+	// chovka
+	{source: "chovka", raw: "Baldur's Gate 3 | RePack от Chovka", want: "Baldur's Gate 3", rules: []string{"strip-repack-tag", "trim"}},
+	{source: "chovka", raw: "Stardew Valley | GOG", want: "Stardew Valley", rules: []string{"strip-repack-tag", "trim"}},
+	{source: "chovka", raw: "Terraria | Portable", want: "Terraria", rules: []string{"strip-repack-tag", "trim"}},
+	{source: "chovka", raw: "Ведьмак 3: Дикая Охота | RePack", want: "Vedmak 3: Dikaya Okhota", rules: []string{"strip-repack-tag", "transliterate-cyrillic", "trim"}},
+	{source: "chovka", raw: "Hogwarts Legacy: Digital Deluxe Edition | RePack by Chovka", want: "Hogwarts Legacy: Digital Deluxe Edition", rules: []string{"strip-repack-tag", "trim"}},
+
+	// steamrip
+	{source: "steamrip", raw: "Hollow Knight Free Download (v1.5.78.11833)", want: "Hollow Knight", rules: []string{"strip-parenthesized", "strip-repack-tag", "collapse-spaces", "trim"}},
+	{source: "steamrip", raw: "Palworld Free Download (v0.3.1.55694)", want: "Palworld", rules: []string{"strip-parenthesized", "strip-repack-tag", "collapse-spaces", "trim"}},
+	{source: "steamrip", raw: "Hades II Free Download (Build 14568052) (Early Access)", want: "Hades II", rules: []string{"strip-parenthesized", "strip-repack-tag", "collapse-spaces", "trim"}},
+	{source: "steamrip", raw: "Lethal Company Free Download", want: "Lethal Company", rules: []string{"strip-repack-tag", "trim"}},
+
+	// armgddn
+	{source: "armgddn", raw: "Lies of P v1.5.0.0-ARMGDDN", want: "Lies of P", rules: []string{"strip-repack-tag", "strip-version-tag", "trim"}},
+	{source: "armgddn", raw: "Half-Life Alyx-ARMGDDN", want: "Half-Life Alyx", rules: []string{"strip-repack-tag"}},
+	{source: "armgddn", raw: "Skyrim VR v1.4.15-ARMGDDN", want: "Skyrim VR", rules: []string{"strip-repack-tag", "strip-version-tag", "trim"}},
+	{source: "armgddn", raw: "  Beat Saber v1.37.0-ARMGDDN", want: "Beat Saber", rules: []string{"strip-repack-tag", "strip-version-tag", "trim"}},
+	{source: "armgddn", raw: "Rev2 Arena v2.1-ARMGDDN", want: "Rev2 Arena", rules: []string{"strip-repack-tag", "strip-version-tag", "trim"}},
+
+	// gnarly
+	{source: "gnarly", raw: "Elden Ring (v1.12.3) [Gnarly Repacks]", want: "Elden Ring", rules: []string{"strip-repack-tag", "strip-parenthesized", "trim"}},
+	{source: "gnarly", raw: "Super Mario Odyssey (Switch Emulated) [Gnarly Repacks]", want: "Super Mario Odyssey", rules: []string{"strip-repack-tag", "strip-parenthesized", "trim"}},
+	{source: "gnarly", raw: "Hades [Gnarly Repacks]", want: "Hades", rules: []string{"strip-repack-tag"}},
+	{source: "gnarly", raw: "Celeste", want: "Celeste"},
+}
+
+func TestCorpus(t *testing.T) {
+	for _, c := range corpus {
+		t.Run(c.source+"/"+c.raw, func(t *testing.T) {
+			n, ok := For(c.source)
+			if !ok {
+				t.Fatalf("no normalizer for %s", c.source)
+			}
+			if got := n.Normalize(c.raw); got != c.want {
+				t.Errorf("Normalize = %q, want %q", got, c.want)
+			}
+			got, steps := n.Explain(c.raw)
+			if got != c.want {
+				t.Errorf("Explain = %q, want %q", got, c.want)
+			}
+			var rules []string
+			before := c.raw
+			for _, step := range steps {
+				rules = append(rules, step.Rule)
+				if step.Before != before || step.After == step.Before {
+					t.Errorf("step %s changed %q to %q after %q", step.Rule, step.Before, step.After, before)
+				}
+				before = step.After
+			}
+			if !slices.Equal(rules, c.rules) {
+				t.Errorf("fired rules %q, want %q", rules, c.rules)
+			}
+		})
+	}
+}
+
+func TestCorpusCoversSources(t *testing.T) {
+	for _, source := range Sources() {
+		if !slices.ContainsFunc(corpus, func(c corpusCase) bool { return c.source == source }) {
+			t.Errorf("no corpus names of %s", source)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package normalize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/nitezs/pcgamedb/utils"
+)
+
+// Rules shared by the sources
+var (
+	StripEditionSuffix = removeRegex("strip-edition-suffix", "remove a dash followed by an edition, bundle or collection name",
+		`(?i)[\-\+]\s?[^:\-]*?\s(Edition|Bundle|Pack|Set|Remake|Collection)`)
+	StripEditionWords = replaceRegex("strip-edition-words", "remove edition, collection and bundle names anywhere",
+		`(?i)[\w’'-]+\s(Edition|Vision|Collection|Bundle|Pack|Deluxe)`, " ")
+	StripRemaster = removeRegex("strip-remaster-suffix", "remove a Remastered suffix after a dash or colon",
+		`(?i)\s*[-:]\s*(Campaign\s+)?Remaster(ed)?\b`)
+	StripGOTY = removeRegex("strip-goty", "remove GOTY",
+		`(?i)\bGOTY\b`)
+	ExpandGOTY = replaceRegex("expand-goty", "spell out GOTY after a colon",
+		`(?i):\sgoty\b`, ": Game Of The Year")
+	StripVersion = cutRegex("strip-version-tag", "cut at a version or build number",
+		`(?i)\bv(er)?\s?\.?\d+(\.\d+)*|\bBuild[\s.]\d+`)
+	StripBracketed = cutAt("strip-bracketed-metadata", "cut at the first bracket, repackers put versions, DLCs and languages there",
+		"(", "[", "{")
+	StripParenthesized = removeRegex("strip-parenthesized", "remove every part in parentheses",
+		`\([^)]*\)`)
+	StripBonusContent = cutAt("strip-bonus-content", "cut at a plus, what follows lists DLCs and bonus content",
+		"+")
+	StripDashSuffix = cutAt("strip-dash-suffix", "cut at an en dash",
+		"–")
+	StripAllInOne = cutAt("strip-all-in-one", "cut at an AiO or All In One suffix",
+		"- AiO", "- All In One")
+	StripPortable = removeStrings("strip-portable", "remove a Portable suffix",
+		"- Portable")
+	StripPlatformTag = removeRegex("strip-platform-tag", "remove NSW for PC and a trailing PC",
+		`(?i)\bnsw for pc\b|\s+PC\s*$`)
+	StripLanguageTag = removeRegex("strip-language-tag", "remove a MULTi language count",
+		`(?i)\.MULTi\d+`)
+	DotsToSpaces = NewRule("dots-to-spaces", "separate words by spaces instead of dots", func(name string) string {
+		return strings.ReplaceAll(name, ".", " ")
+	})
+	PickLatinTitle        = NewRule("pick-latin-title", "keep the longest title without Cyrillic of titles separated by slashes", pickLatinTitle)
+	TransliterateCyrillic = NewRule("transliterate-cyrillic", "write Cyrillic letters in Latin", transliterate)
+	CollapseSpaces        = replaceRegex("collapse-spaces", "replace runs of whitespace by one space",
+		`\s+`, " ")
+	Trim = NewRule("trim", "remove whitespace and separators at both ends", func(name string) string {
+		return strings.TrimFunc(name, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune(":-–|", r)
+		})
+	})
+)
+
+// stripTag returns the rule removing the tag a repacker adds to its names
+func stripTag(pattern string) Rule {
+	return removeRegex("strip-repack-tag", "remove the tag of the repacker", pattern)
+}
+
+func removeRegex(name string, description string, pattern string) Rule {
+	return replaceRegex(name, description, pattern, "")
+}
+
+func replaceRegex(name string, description string, pattern string, replacement string) Rule {
+	re := regexp.MustCompile(pattern)
+	return NewRule(name, description, func(s string) string {
+		return re.ReplaceAllString(s, replacement)
+	})
+}
+
+func cutRegex(name string, description string, pattern string) Rule {
+	re := regexp.MustCompile(pattern)
+	return NewRule(name, description, func(s string) string {
+		if index := re.FindStringIndex(s); index != nil {
+			return s[:index[0]]
+		}
+		return s
+	})
+}
+
+func cutAt(name string, description string, separators ...string) Rule {
+	return NewRule(name, description, func(s string) string {
+		for _, separator := range separators {
+			if index := strings.Index(s, separator); index != -1 {
+				s = s[:index]
+			}
+		}
+		return s
+	})
+}
+
+func removeStrings(name string, description string, olds ...string) Rule {
+	return NewRule(name, description, func(s string) string {
+		for _, old := range olds {
+			s = strings.ReplaceAll(s, old, "")
+		}
+		return s
+	})
+}
+
+func pickLatinTitle(name string) string {
+	if !strings.Contains(name, "/") {
+		return name
+	}
+	longest := ""
+	for _, title := range strings.Split(name, "/") {
+		if !utils.ContainsRussian(title) && len(title) > len(longest) {
+			longest = title
+		}
+	}
+	if strings.TrimSpace(longest) == "" {
+		return name
+	}
+	return longest
+}
@@ -0,0 +1,85 @@
+package normalize
+
+// Normalizers of the sources. Generic is for names that are already game names,
+// it makes the search queries and alias names of game items.
+var (
+	Generic = register(New("generic",
+		StripEditionWords,
+		StripGOTY,
+		StripPlatformTag,
+		StripParenthesized,
+		StripRemaster,
+		CollapseSpaces,
+		Trim,
+	))
+	DODI = register(New("dodi",
+		stripTag(`\s*-?\s*\[DODI Repack\]`),
+		StripRemaster,
+		StripBonusContent,
+		StripDashSuffix,
+		StripBracketed,
+		StripAllInOne,
+		CollapseSpaces,
+		StripEditionSuffix,
+		StripPortable,
+		PickLatinTitle,
+		Trim,
+	))
+	KaOsKrew = register(New("kaoskrew",
+		StripVersion,
+		stripTag(`(?i)\.(REPACK2?|UPDATE)-KaOs`),
+		StripLanguageTag,
+		DotsToSpaces,
+		StripGOTY,
+		CollapseSpaces,
+		Trim,
+	))
+	FreeGOG = register(New("freegog",
+		StripParenthesized,
+		StripVersion,
+		StripBonusContent,
+		ExpandGOTY,
+		CollapseSpaces,
+		Trim,
+	))
+	Xatab = register(New("xatab",
+		StripVersion,
+		StripBracketed,
+		StripBonusContent,
+		StripPlatformTag,
+		PickLatinTitle,
+		TransliterateCyrillic,
+		CollapseSpaces,
+		Trim,
+	))
+	OnlineFix = register(New("onlinefix",
+		stripTag(`по сети`),
+		StripParenthesized,
+		TransliterateCyrillic,
+		CollapseSpaces,
+		Trim,
+	))
+	Chovka = register(New("chovka",
+		stripTag(`\|\s*(RePack|GOG|Portable).*`),
+		TransliterateCyrillic,
+		CollapseSpaces,
+		Trim,
+	))
+	SteamRIP = register(New("steamrip",
+		StripParenthesized,
+		stripTag(`Free Download`),
+		CollapseSpaces,
+		Trim,
+	))
+	ARMGDDN = register(New("armgddn",
+		stripTag(`-ARMGDDN`),
+		StripVersion,
+		Trim,
+	))
+	Gnarly = register(New("gnarly",
+		stripTag(`\s*\[Gnarly Repacks\].*`),
+		StripParenthesized,
+		CollapseSpaces,
+		Trim,
+	))
+)
@@ -0,0 +1,38 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Russian and Ukrainian letters, after the BGN/PCGN romanization without diacritics
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+func transliterate(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		latin, ok := cyrillicToLatin[unicode.ToLower(r)]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		if !unicode.IsUpper(r) || latin == "" {
+			b.WriteString(latin)
+			continue
+		}
+		// all capitals inside words written in capitals, a capital first letter otherwise
+		if i+1 < len(runes) && unicode.IsUpper(runes[i+1]) {
+			b.WriteString(strings.ToUpper(latin))
+		} else {
+			b.WriteString(strings.ToUpper(latin[:1]) + latin[1:])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// errBlockedAddress is returned for hosts that are not on the public internet
+var errBlockedAddress = errors.New("address is not public")
+
+// blockedPrefixes are the non public ranges the netip predicates do not cover
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// publicClient sends the requests to URLs given by users
+var publicClient = NewPublicClient()
+
+// NewPublicClient returns a client for URLs given by users. It only connects to public addresses,
+// checked when connecting so that a name resolving to another address later is blocked too,
+// does not follow redirects and ignores the proxy settings of the server.
+func NewPublicClient() *http.Client {
+	return &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 10 * time.Second,
+				Control: checkDialAddress,
+			}).DialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkDialAddress(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// parseHTTPSURL parses a URL given by a user, only https URLs without credentials are accepted
+func parseHTTPSURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return nil, errors.New("url must be https")
+	}
+	if u.User != nil {
+		return nil, errors.New("url must not contain credentials")
+	}
+	return u, nil
+}
+
+// checkPublicHost resolves the host of rawURL and fails if any of its addresses is not public
+func checkPublicHost(ctx context.Context, rawURL string) error {
+	u, err := parseHTTPSURL(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("%s: %w", u.Hostname(), errBlockedAddress)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+)
+
+const discordColor = 0x5865f2
+
+// webhook URLs are pinned to Discord, a notifier must not post anywhere else
+const (
+	discordHost        = "discord.com"
+	discordWebhookPath = "/api/webhooks/"
+)
+
+func init() {
+	Register(model.NotifierDiscord, newDiscord)
+}
+
+type discord struct {
+	settings *model.DiscordSettings
+}
+
+func newDiscord(n *model.Notifier) (Sender, error) {
+	if n.Discord == nil {
+		return nil, errMissingSettings
+	}
+	if n.Discord.WebhookURL == "" {
+		return nil, errors.New("discord notifier needs webhook_url")
+	}
+	u, err := parseHTTPSURL(n.Discord.WebhookURL)
+	if err != nil || u.Host != discordHost || !strings.HasPrefix(u.Path, discordWebhookPath) {
+		return nil, errors.New("discord webhook_url must start with https://" + discordHost + discordWebhookPath)
+	}
+	return &discord{settings: n.Discord}, nil
+}
+
+func (d *discord) endpoint() string {
+	return d.settings.WebhookURL
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbedImage struct {
+	URL string `json:"url"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	URL         string              `json:"url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Thumbnail   *discordEmbedImage  `json:"thumbnail,omitempty"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+func (d *discord) Send(ctx context.Context, msg *Message) error {
+	embed := discordEmbed{
+		Title:     msg.Title(),
+		URL:       msg.Link,
+		Color:     discordColor,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if msg.RawName != msg.Name {
+		embed.Description = msg.RawName
+	}
+	if msg.Cover != "" {
+		embed.Thumbnail = &discordEmbedImage{URL: msg.Cover}
+	}
+	if msg.Size != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Size", Value: msg.Size, Inline: true})
+	}
+	if msg.Author != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Author", Value: msg.Author, Inline: true})
+	}
+	_, err := doJSON(ctx, publicClient, http.MethodPost, d.settings.WebhookURL, nil, map[string]any{
+		"username": "pcgamedb",
+		"embeds":   []discordEmbed{embed},
+	})
+	if err != nil {
+		return errors.New("discord: " + err.Error())
+	}
+	return nil
+}
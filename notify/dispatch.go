@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/events"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	notifiersTTL = time.Minute
+	sendTimeout  = 30 * time.Second
+)
+
+var (
+	initOnce = &sync.Once{}
+	pending  = &sync.WaitGroup{}
+
+	notifiersMutx     = &sync.Mutex{}
+	notifiers         []*model.Notifier
+	notifiersLoadedAt time.Time
+)
+
+// Init sends notifications for the items this process links to a game info,
+// and for organized items that get a new version
+func Init() {
+	initOnce.Do(func() {
+		events.AddHook(func(e *events.Event) {
+			switch e.Type {
+			case events.InfoOrganized, events.ItemUpdated:
+				pending.Add(1)
+				go func() {
+					defer pending.Done()
+					dispatch(e)
+				}()
+			}
+		})
+	})
+}
+
+// Wait blocks until the notifications of the published events are sent
+func Wait() {
+	pending.Wait()
+}
+
+// Invalidate drops the cached notifiers after they were changed
+func Invalidate() {
+	notifiersMutx.Lock()
+	defer notifiersMutx.Unlock()
+	notifiers = nil
+	notifiersLoadedAt = time.Time{}
+}
+
+func activeNotifiers() ([]*model.Notifier, error) {
+	notifiersMutx.Lock()
+	defer notifiersMutx.Unlock()
+	if notifiers != nil && time.Since(notifiersLoadedAt) < notifiersTTL {
+		return notifiers, nil
+	}
+	all, err := db.GetAllNotifiers()
+	if err != nil {
+		return nil, err
+	}
+	notifiers = make([]*model.Notifier, 0, len(all))
+	for _, n := range all {
+		if n.Active {
+			notifiers = append(notifiers, n)
+		}
+	}
+	notifiersLoadedAt = time.Now()
+	return notifiers, nil
+}
+
+func dispatch(e *events.Event) {
+	active, err := activeNotifiers()
+	if err != nil {
+		log.Logger.Warn("Failed to load notifiers", zap.Error(err))
+		return
+	}
+	if len(active) == 0 {
+		return
+	}
+	watchers, err := watchlistEntries()
+	if err != nil {
+		log.Logger.Warn("Failed to load watchlist", zap.Error(err))
+	}
+	switch data := e.Data.(type) {
+	case events.InfoOrganizedData:
+		items, err := db.GetGameItemsByIDs(data.GameIDs)
+		if err != nil {
+			log.Logger.Warn("Failed to get organized game items", zap.Error(err))
+			return
+		}
+		for _, item := range items {
+			notifyItem(active, watchers, item, data.Info, false)
+		}
+	case model.GameItem:
+		// items that are not organized yet are notified when they are linked
+		infos, err := db.GetGameInfosByGameItemIDs([]primitive.ObjectID{data.ID})
+		if err != nil {
+			log.Logger.Warn("Failed to get game info of updated item", zap.Error(err))
+			return
+		}
+		if info, ok := infos[data.ID]; ok {
+			notifyItem(active, watchers, &data, info, true)
+		}
+	}
+}
+
+// notifyItem sends the item to the notifiers whose filters accept it,
+// and to the notifiers of the watchlist entries it matches
+func notifyItem(active []*model.Notifier, watchers []*model.WatchlistEntry, item *model.GameItem, info *model.GameInfo, updated bool) {
+	msg := NewMessage(item, info, updated)
+	// a notifier gets an item once, even if several filters and entries match it
+	sent := map[primitive.ObjectID]bool{}
+	for _, n := range active {
+		if Match(n, item, info) {
+			sent[n.ID] = send(n, msg)
+		}
+	}
+	if info == nil {
+		return
+	}
+	for _, entry := range watchers {
+		if !MatchWatchlistEntry(entry, item, info) {
+			continue
+		}
+		ok, done := sent[entry.NotifierID]
+		if !done {
+			idx := slices.IndexFunc(active, func(n *model.Notifier) bool { return n.ID == entry.NotifierID })
+			if idx < 0 {
+				continue
+			}
+			ok = send(active[idx], msg)
+			sent[entry.NotifierID] = ok
+		}
+		if ok {
+			if err := db.SetWatchlistEntryNotified(entry.ID); err != nil {
+				log.Logger.Warn("Failed to update watchlist entry", zap.Error(err))
+			}
+		}
+	}
+}
+
+func send(n *model.Notifier, msg *Message) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	if err := Send(ctx, n, msg); err != nil {
+		log.Logger.Warn("Failed to send notification",
+			zap.String("notifier", n.Name),
+			zap.String("type", n.Type),
+			zap.String("game", msg.Name),
+			zap.Error(err),
+		)
+		return false
+	}
+	return true
+}
+
+// Match reports whether the author and game filters of the notifier accept the item,
+// watchlist-only notifiers accept none
+func Match(n *model.Notifier, item *model.GameItem, info *model.GameInfo) bool {
+	if n.WatchlistOnly {
+		return false
+	}
+	if len(n.Authors) > 0 && !slices.ContainsFunc(n.Authors, func(author string) bool {
+		return strings.EqualFold(author, item.Author)
+	}) {
+		return false
+	}
+	if len(n.GameInfoIDs) > 0 && (info == nil || !slices.Contains(n.GameInfoIDs, info.ID)) {
+		return false
+	}
+	return true
+}
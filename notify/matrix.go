@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+)
+
+func init() {
+	Register(model.NotifierMatrix, newMatrix)
+}
+
+var matrixTxnCounter atomic.Uint64
+
+type matrix struct {
+	settings *model.MatrixSettings
+}
+
+func newMatrix(n *model.Notifier) (Sender, error) {
+	if n.Matrix == nil {
+		return nil, errMissingSettings
+	}
+	if n.Matrix.Homeserver == "" || n.Matrix.AccessToken == "" || n.Matrix.RoomID == "" {
+		return nil, errors.New("matrix notifier needs homeserver, access_token and room_id")
+	}
+	if _, err := parseHTTPSURL(n.Matrix.Homeserver); err != nil {
+		return nil, errors.New("matrix homeserver " + err.Error())
+	}
+	return &matrix{settings: n.Matrix}, nil
+}
+
+func (m *matrix) endpoint() string {
+	return m.settings.Homeserver
+}
+
+// Send posts an m.text event, images need an upload to the homeserver so the cover is left out
+func (m *matrix) Send(ctx context.Context, msg *Message) error {
+	txnID := fmt.Sprintf("pcgamedb-%d-%d", time.Now().UnixNano(), matrixTxnCounter.Add(1))
+	u := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(m.settings.Homeserver, "/"),
+		url.PathEscape(m.settings.RoomID),
+		txnID,
+	)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+m.settings.AccessToken)
+	_, err := doJSON(ctx, publicClient, http.MethodPut, u, header, map[string]any{
+		"msgtype":        "m.text",
+		"body":           msg.Text(),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": strings.ReplaceAll(msg.HTML(), "\n", "<br>"),
+	})
+	if err != nil {
+		return errors.New("matrix: " + err.Error())
+	}
+	return nil
+}
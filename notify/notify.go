@@ -0,0 +1,188 @@
+// Package notify sends formatted notifications about new and updated game items
+// to chat platforms. Each platform is a plugin registered with Register.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+)
+
+// Message is a game item and the game info it is organized into, ready to be rendered
+type Message struct {
+	Updated bool
+	Name    string
+	RawName string
+	Cover   string
+	Size    string
+	Author  string
+	Link    string
+}
+
+// NewMessage builds the message of an item, info may be nil if the item is not organized yet
+func NewMessage(item *model.GameItem, info *model.GameInfo, updated bool) *Message {
+	msg := &Message{
+		Updated: updated,
+		Name:    item.Name,
+		RawName: item.RawName,
+		Size:    item.Size,
+		Author:  item.Author,
+		Link:    item.Url,
+	}
+	if info != nil {
+		msg.Name = info.Name
+		msg.Cover = info.Cover
+	}
+	if msg.Name == "" {
+		msg.Name = item.RawName
+	}
+	return msg
+}
+
+func (m *Message) Title() string {
+	if m.Updated {
+		return "Updated: " + m.Name
+	}
+	return "New: " + m.Name
+}
+
+// Text renders the message as plain text
+func (m *Message) Text() string {
+	var b strings.Builder
+	b.WriteString(m.Title())
+	if m.RawName != "" && m.RawName != m.Name {
+		b.WriteString("\n" + m.RawName)
+	}
+	if m.Size != "" {
+		b.WriteString("\nSize: " + m.Size)
+	}
+	if m.Author != "" {
+		b.WriteString("\nAuthor: " + m.Author)
+	}
+	if m.Link != "" {
+		b.WriteString("\n" + m.Link)
+	}
+	return b.String()
+}
+
+// HTML renders the message with the tags supported by both Telegram and Matrix
+func (m *Message) HTML() string {
+	var b strings.Builder
+	b.WriteString("<b>" + html.EscapeString(m.Title()) + "</b>")
+	if m.RawName != "" && m.RawName != m.Name {
+		b.WriteString("\n<i>" + html.EscapeString(m.RawName) + "</i>")
+	}
+	if m.Size != "" {
+		b.WriteString("\nSize: " + html.EscapeString(m.Size))
+	}
+	if m.Author != "" {
+		b.WriteString("\nAuthor: " + html.EscapeString(m.Author))
+	}
+	if m.Link != "" {
+		b.WriteString(fmt.Sprintf("\n<a href=\"%s\">%s</a>", html.EscapeString(m.Link), html.EscapeString(m.Link)))
+	}
+	return b.String()
+}
+
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Builder validates the settings of a notifier and returns its sender
+type Builder func(n *model.Notifier) (Sender, error)
+
+var builders = map[string]Builder{}
+
+// Register adds a notifier type, it is called from the init of each plugin
+func Register(typ string, builder Builder) {
+	builders[typ] = builder
+}
+
+// Types returns the registered notifier types
+func Types() []string {
+	res := make([]string, 0, len(builders))
+	for typ := range builders {
+		res = append(res, typ)
+	}
+	return res
+}
+
+// New returns the sender of a notifier, or an error if its settings are invalid
+func New(n *model.Notifier) (Sender, error) {
+	builder, ok := builders[n.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier type: %s", n.Type)
+	}
+	return builder(n)
+}
+
+// endpoint is implemented by the senders that post to a URL given by the user
+type endpoint interface {
+	endpoint() string
+}
+
+// Validate checks the settings of a notifier like New, and that the URL it posts to
+// resolves to public addresses only
+func Validate(ctx context.Context, n *model.Notifier) error {
+	sender, err := New(n)
+	if err != nil {
+		return err
+	}
+	if e, ok := sender.(endpoint); ok {
+		return checkPublicHost(ctx, e.endpoint())
+	}
+	return nil
+}
+
+// Send sends msg to the notifier
+func Send(ctx context.Context, n *model.Notifier, msg *Message) error {
+	sender, err := New(n)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, msg)
+}
+
+// client sends the requests to URLs of the configuration
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// doJSON sends body as JSON and returns the response body, non 2xx responses are errors.
+// The errors contain the response body, they are for the server logs only.
+func doJSON(ctx context.Context, client *http.Client, method string, url string, header http.Header, body any) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+var errMissingSettings = errors.New("missing notifier settings")
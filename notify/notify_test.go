@@ -0,0 +1,291 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// capturedRequest is a request received by the stub server
+type capturedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]any
+}
+
+// stubServer starts a TLS server answering every request with status and response,
+// and points the clients of the package at it, whatever host a URL names
+func stubServer(t *testing.T, status int, response string) *[]capturedRequest {
+	t.Helper()
+	var requests []capturedRequest
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		req := capturedRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header}
+		if err := json.Unmarshal(data, &req.Body); err != nil {
+			t.Errorf("request body is not JSON: %s", data)
+		}
+		requests = append(requests, req)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	stub := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+			},
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	oldClient, oldPublicClient := client, publicClient
+	client, publicClient = stub, stub
+	oldAPIURL := config.Config.Notify.TelegramAPIURL
+	config.Config.Notify.TelegramAPIURL = "https://telegram.test/"
+	t.Cleanup(func() {
+		client, publicClient = oldClient, oldPublicClient
+		config.Config.Notify.TelegramAPIURL = oldAPIURL
+	})
+	return &requests
+}
+
+func testMessage() *Message {
+	return &Message{
+		Name:    "Hollow Knight",
+		RawName: "Hollow Knight v1.5.78 <GOG>",
+		Size:    "9.5 GB",
+		Author:  "dodi",
+		Link:    "https://example.com/hollow-knight",
+	}
+}
+
+func TestTelegramSend(t *testing.T) {
+	tests := []struct {
+		name   string
+		cover  string
+		method string
+		text   string
+	}{
+		{name: "text", method: "sendMessage", text: "text"},
+		{name: "photo", cover: "https://example.com/cover.jpg", method: "sendPhoto", text: "caption"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := stubServer(t, http.StatusOK, `{"ok":true}`)
+			msg := testMessage()
+			msg.Cover = tt.cover
+			err := Send(context.Background(), &model.Notifier{
+				Type:     model.NotifierTelegram,
+				Telegram: &model.TelegramSettings{BotToken: "123:secret", ChatID: "-100"},
+			}, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(*requests))
+			}
+			req := (*requests)[0]
+			if req.Method != http.MethodPost || req.Path != "/bot123:secret/"+tt.method {
+				t.Errorf("got %s %s, want POST /bot123:secret/%s", req.Method, req.Path, tt.method)
+			}
+			if req.Body["chat_id"] != "-100" || req.Body["parse_mode"] != "HTML" {
+				t.Errorf("got body %v", req.Body)
+			}
+			if req.Body[tt.text] != msg.HTML() {
+				t.Errorf("got %s %q, want %q", tt.text, req.Body[tt.text], msg.HTML())
+			}
+			if tt.cover != "" && req.Body["photo"] != tt.cover {
+				t.Errorf("got photo %q, want %q", req.Body["photo"], tt.cover)
+			}
+		})
+	}
+}
+
+func TestTelegramSendError(t *testing.T) {
+	n := &model.Notifier{
+		Type:     model.NotifierTelegram,
+		Telegram: &model.TelegramSettings{BotToken: "123:secret", ChatID: "-100"},
+	}
+	t.Run("not ok", func(t *testing.T) {
+		stubServer(t, http.StatusOK, `{"ok":false,"description":"chat not found"}`)
+		err := Send(context.Background(), n, testMessage())
+		if err == nil || !strings.Contains(err.Error(), "chat not found") {
+			t.Errorf("got %v, want the description of the error", err)
+		}
+	})
+	t.Run("token redacted", func(t *testing.T) {
+		stubServer(t, http.StatusNotFound, `no bot 123:secret`)
+		err := Send(context.Background(), n, testMessage())
+		if err == nil || strings.Contains(err.Error(), "123:secret") {
+			t.Errorf("got %v, want an error without the token", err)
+		}
+	})
+}
+
+func TestDiscordSend(t *testing.T) {
+	requests := stubServer(t, http.StatusNoContent, "")
+	msg := testMessage()
+	msg.Cover = "https://example.com/cover.jpg"
+	err := Send(context.Background(), &model.Notifier{
+		Type:    model.NotifierDiscord,
+		Discord: &model.DiscordSettings{WebhookURL: "https://discord.com/api/webhooks/1/token"},
+	}, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*requests))
+	}
+	req := (*requests)[0]
+	if req.Method != http.MethodPost || req.Path != "/api/webhooks/1/token" {
+		t.Errorf("got %s %s, want POST /api/webhooks/1/token", req.Method, req.Path)
+	}
+	data, _ := json.Marshal(req.Body["embeds"])
+	var embeds []discordEmbed
+	if err := json.Unmarshal(data, &embeds); err != nil || len(embeds) != 1 {
+		t.Fatalf("got embeds %s", data)
+	}
+	embed := embeds[0]
+	if embed.Title != "New: Hollow Knight" || embed.URL != msg.Link || embed.Description != msg.RawName {
+		t.Errorf("got embed %+v", embed)
+	}
+	if embed.Thumbnail == nil || embed.Thumbnail.URL != msg.Cover {
+		t.Errorf("got thumbnail %+v, want %s", embed.Thumbnail, msg.Cover)
+	}
+	want := []discordEmbedField{{Name: "Size", Value: "9.5 GB", Inline: true}, {Name: "Author", Value: "dodi", Inline: true}}
+	if len(embed.Fields) != len(want) || embed.Fields[0] != want[0] || embed.Fields[1] != want[1] {
+		t.Errorf("got fields %+v, want %+v", embed.Fields, want)
+	}
+}
+
+func TestDiscordWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://discord.com/api/webhooks/1/token", valid: true},
+		{url: "http://discord.com/api/webhooks/1/token"},
+		{url: "https://discord.com.example.com/api/webhooks/1/token"},
+		{url: "https://discord.com:8443/api/webhooks/1/token"},
+		{url: "https://discord.com/api/users/1"},
+		{url: "https://127.0.0.1/api/webhooks/1/token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, err := New(&model.Notifier{Type: model.NotifierDiscord, Discord: &model.DiscordSettings{WebhookURL: tt.url}})
+			if (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestMatrixSend(t *testing.T) {
+	requests := stubServer(t, http.StatusOK, `{"event_id":"$1"}`)
+	msg := testMessage()
+	err := Send(context.Background(), &model.Notifier{
+		Type: model.NotifierMatrix,
+		Matrix: &model.MatrixSettings{
+			Homeserver:  "https://matrix.example.org/",
+			AccessToken: "token",
+			RoomID:      "!room:example.org",
+		},
+	}, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*requests))
+	}
+	req := (*requests)[0]
+	prefix := "/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/pcgamedb-"
+	if req.Method != http.MethodPut || !strings.HasPrefix(req.Path, prefix) {
+		t.Errorf("got %s %s, want PUT %s...", req.Method, req.Path, prefix)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "Bearer token" {
+		t.Errorf("got Authorization %q", auth)
+	}
+	if req.Body["msgtype"] != "m.text" || req.Body["body"] != msg.Text() || req.Body["format"] != "org.matrix.custom.html" {
+		t.Errorf("got body %v", req.Body)
+	}
+	if body, _ := req.Body["formatted_body"].(string); strings.Contains(body, "\n") || !strings.Contains(body, "<br>") {
+		t.Errorf("got formatted_body %q, want lines joined with <br>", body)
+	}
+}
+
+func TestPublicClientBlocksInternalAddresses(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the server")
+	}))
+	defer srv.Close()
+	_, err := doJSON(context.Background(), publicClient, http.MethodPost, srv.URL, nil, map[string]any{})
+	if !errors.Is(err, errBlockedAddress) {
+		t.Errorf("got %v, want %v", err, errBlockedAddress)
+	}
+	if err := checkPublicHost(context.Background(), "https://127.0.0.1"); !errors.Is(err, errBlockedAddress) {
+		t.Errorf("got %v, want %v", err, errBlockedAddress)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	gameID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	item := &model.GameItem{Author: "DODI"}
+	info := &model.GameInfo{ID: gameID}
+	tests := []struct {
+		name     string
+		notifier *model.Notifier
+		info     *model.GameInfo
+		want     bool
+	}{
+		{name: "no filters", notifier: &model.Notifier{}, info: info, want: true},
+		{name: "author ignores case", notifier: &model.Notifier{Authors: []string{"fitgirl", "dodi"}}, info: info, want: true},
+		{name: "other author", notifier: &model.Notifier{Authors: []string{"fitgirl"}}, info: info},
+		{name: "game", notifier: &model.Notifier{GameInfoIDs: []primitive.ObjectID{otherID, gameID}}, info: info, want: true},
+		{name: "other game", notifier: &model.Notifier{GameInfoIDs: []primitive.ObjectID{otherID}}, info: info},
+		{name: "game of unorganized item", notifier: &model.Notifier{GameInfoIDs: []primitive.ObjectID{gameID}}},
+		{name: "author and game", notifier: &model.Notifier{Authors: []string{"dodi"}, GameInfoIDs: []primitive.ObjectID{gameID}}, info: info, want: true},
+		{name: "author but other game", notifier: &model.Notifier{Authors: []string{"dodi"}, GameInfoIDs: []primitive.ObjectID{otherID}}, info: info},
+		{name: "watchlist only", notifier: &model.Notifier{WatchlistOnly: true}, info: info},
+		{name: "watchlist only with game", notifier: &model.Notifier{WatchlistOnly: true, GameInfoIDs: []primitive.ObjectID{gameID}}, info: info},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.notifier, item, tt.info); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyItemWatchlistOnly(t *testing.T) {
+	requests := stubServer(t, http.StatusNoContent, "")
+	discord := &model.DiscordSettings{WebhookURL: "https://discord.com/api/webhooks/1/token"}
+	broadcast := &model.Notifier{ID: primitive.NewObjectID(), Type: model.NotifierDiscord, Discord: discord}
+	watchlistOnly := &model.Notifier{ID: primitive.NewObjectID(), Type: model.NotifierDiscord, Discord: discord, WatchlistOnly: true}
+	// the only entry of the watchlist-only notifier watches another game
+	watchers := []*model.WatchlistEntry{{ID: primitive.NewObjectID(), SteamID: 2, NotifierID: watchlistOnly.ID}}
+	item := &model.GameItem{RawName: "Hollow Knight v1.5.78", Author: "dodi"}
+	info := &model.GameInfo{ID: primitive.NewObjectID(), Name: "Hollow Knight", SteamID: 1}
+
+	notifyItem([]*model.Notifier{watchlistOnly}, watchers, item, info, false)
+	if len(*requests) != 0 {
+		t.Fatalf("got %d requests for an unwatched game, want 0", len(*requests))
+	}
+	notifyItem([]*model.Notifier{broadcast, watchlistOnly}, watchers, item, info, false)
+	if len(*requests) != 1 {
+		t.Fatalf("got %d requests, want 1 from the broadcast notifier", len(*requests))
+	}
+}
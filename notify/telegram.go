@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/model"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+// telegramCaptionLimit is the length limit of photo captions, longer messages are sent as text
+const telegramCaptionLimit = 1024
+
+func init() {
+	Register(model.NotifierTelegram, newTelegram)
+}
+
+type telegram struct {
+	settings *model.TelegramSettings
+}
+
+func newTelegram(n *model.Notifier) (Sender, error) {
+	if n.Telegram == nil {
+		return nil, errMissingSettings
+	}
+	if n.Telegram.BotToken == "" || n.Telegram.ChatID == "" {
+		return nil, errors.New("telegram notifier needs bot_token and chat_id")
+	}
+	return &telegram{settings: n.Telegram}, nil
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (t *telegram) Send(ctx context.Context, msg *Message) error {
+	text := msg.HTML()
+	method := "sendMessage"
+	body := map[string]any{
+		"chat_id":    t.settings.ChatID,
+		"text":       text,
+		"parse_mode": "HTML",
+	}
+	if msg.Cover != "" && len(text) <= telegramCaptionLimit {
+		method = "sendPhoto"
+		body = map[string]any{
+			"chat_id":    t.settings.ChatID,
+			"photo":      msg.Cover,
+			"caption":    text,
+			"parse_mode": "HTML",
+		}
+	}
+	// the Bot API server is only configurable by the server owner, so it may be a local one
+	apiURL := config.Config.Notify.TelegramAPIURL
+	if apiURL == "" {
+		apiURL = telegramAPIURL
+	}
+	url := fmt.Sprintf("%s/bot%s/%s", strings.TrimSuffix(apiURL, "/"), t.settings.BotToken, method)
+	data, err := doJSON(ctx, client, http.MethodPost, url, nil, body)
+	if err != nil {
+		// the token is part of the url, keep it out of the logs
+		return errors.New(strings.ReplaceAll(err.Error(), t.settings.BotToken, "***"))
+	}
+	var resp telegramResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("telegram: %s", resp.Description)
+	}
+	return nil
+}
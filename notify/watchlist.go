@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
+)
+
+var (
+	watchlistMutx     = &sync.Mutex{}
+	watchlist         []*model.WatchlistEntry
+	watchlistLoadedAt time.Time
+)
+
+// InvalidateWatchlist drops the cached watchlist after it was changed
+func InvalidateWatchlist() {
+	watchlistMutx.Lock()
+	defer watchlistMutx.Unlock()
+	watchlist = nil
+	watchlistLoadedAt = time.Time{}
+}
+
+func watchlistEntries() ([]*model.WatchlistEntry, error) {
+	watchlistMutx.Lock()
+	defer watchlistMutx.Unlock()
+	if watchlist != nil && time.Since(watchlistLoadedAt) < notifiersTTL {
+		return watchlist, nil
+	}
+	all, err := db.GetAllWatchlistEntries()
+	if err != nil {
+		return nil, err
+	}
+	if all == nil {
+		all = []*model.WatchlistEntry{}
+	}
+	watchlist = all
+	watchlistLoadedAt = time.Now()
+	return watchlist, nil
+}
+
+// MatchWatchlistEntry reports whether the item, organized into info, is the game the entry watches
+func MatchWatchlistEntry(entry *model.WatchlistEntry, item *model.GameItem, info *model.GameInfo) bool {
+	if len(entry.Authors) > 0 && !slices.ContainsFunc(entry.Authors, func(author string) bool {
+		return strings.EqualFold(author, item.Author)
+	}) {
+		return false
+	}
+	switch {
+	case entry.SteamID != 0:
+		return info.SteamID == entry.SteamID
+	case entry.IGDBID != 0:
+		return info.IGDBID == entry.IGDBID
+	case !entry.GameInfoID.IsZero():
+		return info.ID == entry.GameInfoID
+	case entry.Name != "":
+		query := " " + utils.NormalizeForIndex(entry.Name) + " "
+		for _, name := range append([]string{info.Name}, info.Aliases...) {
+			if strings.Contains(" "+utils.NormalizeForIndex(name)+" ", query) {
+				return true
+			}
+		}
+	}
+	return false
+}
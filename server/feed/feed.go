@@ -0,0 +1,291 @@
+// Package feed renders lists of game items as Atom, RSS and JSON feeds.
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Feed struct {
+	ID      string
+	Title   string
+	Link    string
+	Self    string
+	Updated time.Time
+	Entries []*Entry
+}
+
+type Entry struct {
+	ID        string
+	Title     string
+	Link      string
+	Source    string
+	Image     string
+	Author    string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+// FromGameItems builds a feed from items, linking each entry to the game info it is organized into.
+// baseURL is the public URL of the API, self the URL of the feed itself.
+func FromGameItems(title string, baseURL string, self string, items []*model.GameItem, infos map[primitive.ObjectID]*model.GameInfo) *Feed {
+	baseURL = strings.TrimRight(baseURL, "/")
+	f := &Feed{
+		ID:    self,
+		Title: title,
+		Link:  baseURL + "/",
+		Self:  self,
+	}
+	for _, item := range items {
+		entry := &Entry{
+			ID:        "urn:pcgamedb:game-item:" + item.ID.Hex(),
+			Title:     item.Name,
+			Link:      baseURL + "/game/raw/id/" + item.ID.Hex(),
+			Source:    item.Url,
+			Author:    item.Author,
+			Published: item.CreatedAt,
+			Updated:   item.UpdatedAt,
+		}
+		info := infos[item.ID]
+		if info != nil {
+			entry.Title = info.Name
+			entry.Link = baseURL + "/game/id/" + info.ID.Hex()
+			entry.Image = info.Cover
+		}
+		entry.Content = entryContent(item, info)
+		if entry.Updated.IsZero() {
+			entry.Updated = entry.Published
+		}
+		if entry.Updated.After(f.Updated) {
+			f.Updated = entry.Updated
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Unix(0, 0)
+	}
+	return f
+}
+
+func entryContent(item *model.GameItem, info *model.GameInfo) string {
+	var builder strings.Builder
+	if info != nil && info.Cover != "" {
+		fmt.Fprintf(&builder, `<p><img src="%s" alt="%s"/></p>`, html.EscapeString(info.Cover), html.EscapeString(info.Name))
+	}
+	if info != nil && info.Description != "" {
+		fmt.Fprintf(&builder, "<p>%s</p>", html.EscapeString(info.Description))
+	}
+	builder.WriteString("<ul>")
+	fmt.Fprintf(&builder, "<li>Name: %s</li>", html.EscapeString(item.RawName))
+	if item.Size != "" {
+		fmt.Fprintf(&builder, "<li>Size: %s</li>", html.EscapeString(item.Size))
+	}
+	fmt.Fprintf(&builder, "<li>Author: %s</li>", html.EscapeString(item.Author))
+	if item.Url != "" {
+		fmt.Fprintf(&builder, `<li>Source: <a href="%s">%s</a></li>`, html.EscapeString(item.Url), html.EscapeString(item.Url))
+	}
+	builder.WriteString("</ul>")
+	return builder.String()
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []atomLink   `xml:"link"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Links     []atomLink  `xml:"link"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (f *Feed) Atom() ([]byte, error) {
+	feed := &atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: f.Self},
+			{Rel: "alternate", Href: f.Link},
+		},
+	}
+	for _, e := range f.Entries {
+		entry := &atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Updated: e.Updated.UTC().Format(time.RFC3339),
+			Links:   []atomLink{{Rel: "alternate", Href: e.Link}},
+			Content: atomContent{Type: "html", Body: e.Content},
+		}
+		if !e.Published.IsZero() {
+			entry.Published = e.Published.UTC().Format(time.RFC3339)
+		}
+		if e.Source != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "related", Href: e.Source})
+		}
+		if e.Image != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Type: "image/jpeg", Href: e.Image})
+		}
+		if e.Author != "" {
+			entry.Author = &atomAuthor{Name: e.Author}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return marshalXML(feed)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        rssGUID       `xml:"guid"`
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Category    string        `xml:"category,omitempty"`
+	Description string        `xml:"description"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+func (f *Feed) RSS() ([]byte, error) {
+	feed := &rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, e := range f.Entries {
+		item := &rssItem{
+			GUID:        rssGUID{Value: e.ID},
+			Title:       e.Title,
+			Link:        e.Link,
+			Category:    e.Author,
+			Description: e.Content,
+			PubDate:     e.Updated.UTC().Format(time.RFC1123Z),
+		}
+		if e.Image != "" {
+			item.Enclosure = &rssEnclosure{URL: e.Image, Type: "image/jpeg"}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return marshalXML(feed)
+}
+
+func marshalXML(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// jsonFeed is a JSON Feed 1.1 document, see https://jsonfeed.org/version/1.1
+type jsonFeed struct {
+	Version     string      `json:"version"`
+	Title       string      `json:"title"`
+	HomePageURL string      `json:"home_page_url"`
+	FeedURL     string      `json:"feed_url"`
+	Items       []*jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	ExternalURL   string       `json:"external_url,omitempty"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	Image         string       `json:"image,omitempty"`
+	DatePublished string       `json:"date_published,omitempty"`
+	DateModified  string       `json:"date_modified"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func (f *Feed) JSON() ([]byte, error) {
+	feed := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.Self,
+		Items:       make([]*jsonItem, 0, len(f.Entries)),
+	}
+	for _, e := range f.Entries {
+		item := &jsonItem{
+			ID:           e.ID,
+			URL:          e.Link,
+			ExternalURL:  e.Source,
+			Title:        e.Title,
+			ContentHTML:  e.Content,
+			Image:        e.Image,
+			DateModified: e.Updated.UTC().Format(time.RFC3339),
+		}
+		if !e.Published.IsZero() {
+			item.DatePublished = e.Published.UTC().Format(time.RFC3339)
+		}
+		if e.Author != "" {
+			item.Authors = []jsonAuthor{{Name: e.Author}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return json.MarshalIndent(feed, "", "  ")
+}
@@ -0,0 +1,22 @@
+// Package graph serves the GraphQL API over game infos, game items, authors and rankings.
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+var schema = graphql.MustParseSchema(schemaString, &queryResolver{},
+	graphql.MaxDepth(8),
+	graphql.MaxParallelism(10),
+)
+
+// Exec runs a GraphQL query, the dataloaders live as long as the query
+func Exec(ctx context.Context, query string, operationName string, variables map[string]interface{}) *graphql.Response {
+	return schema.Exec(withLoaders(ctx), query, operationName, variables)
+}
@@ -0,0 +1,221 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// loaders are created per request and cache what has been loaded for it
+type loaders struct {
+	gameItems   *gameItemLoader
+	rankings    *rankingLoader
+	authorGames *authorGamesLoader
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders())
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+	return newLoaders()
+}
+
+func newLoaders() *loaders {
+	return &loaders{
+		gameItems:   newGameItemLoader(),
+		rankings:    newRankingLoader(),
+		authorGames: newAuthorGamesLoader(),
+	}
+}
+
+// gameItemLoader batches game item lookups.
+// Resolvers returning game infos prime the ids of their items, the first load then
+// fetches every primed id with a single query instead of one query per game info.
+type gameItemLoader struct {
+	mu      sync.Mutex
+	pending map[primitive.ObjectID]struct{}
+	items   map[primitive.ObjectID]*model.GameItem
+}
+
+func newGameItemLoader() *gameItemLoader {
+	return &gameItemLoader{
+		pending: make(map[primitive.ObjectID]struct{}),
+		items:   make(map[primitive.ObjectID]*model.GameItem),
+	}
+}
+
+// seed caches items that were already loaded along with their game infos
+func (l *gameItemLoader) seed(items ...*model.GameItem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, item := range items {
+		l.items[item.ID] = item
+		delete(l.pending, item.ID)
+	}
+}
+
+// prime queues ids for the next batch
+func (l *gameItemLoader) prime(ids ...primitive.ObjectID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue(ids)
+}
+
+func (l *gameItemLoader) queue(ids []primitive.ObjectID) {
+	for _, id := range ids {
+		if _, ok := l.items[id]; !ok {
+			l.pending[id] = struct{}{}
+		}
+	}
+}
+
+// loadMany returns the items of ids in order, missing items are skipped
+func (l *gameItemLoader) loadMany(ids []primitive.ObjectID) ([]*model.GameItem, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue(ids)
+	if len(l.pending) > 0 {
+		batch := make([]primitive.ObjectID, 0, len(l.pending))
+		for id := range l.pending {
+			batch = append(batch, id)
+		}
+		items, err := db.GetGameItemsByIDs(batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range batch {
+			l.items[id] = nil
+		}
+		for _, item := range items {
+			l.items[item.ID] = item
+		}
+		l.pending = make(map[primitive.ObjectID]struct{})
+	}
+	res := make([]*model.GameItem, 0, len(ids))
+	for _, id := range ids {
+		if item := l.items[id]; item != nil {
+			res = append(res, item)
+		}
+	}
+	return res, nil
+}
+
+// rankingLoader loads each ranking at most once per request
+type rankingLoader struct {
+	mu       sync.Mutex
+	rankings map[string][]primitive.ObjectID
+}
+
+func newRankingLoader() *rankingLoader {
+	return &rankingLoader{rankings: make(map[string][]primitive.ObjectID)}
+}
+
+// load returns the IDs of the game infos in a ranking, in ranking order
+func (l *rankingLoader) load(rankingType string) ([]primitive.ObjectID, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rank, ok := l.rankings[rankingType]; ok {
+		return rank, nil
+	}
+	rank, err := crawler.GetSteam250RankingCache(rankingType)
+	if err != nil {
+		return nil, err
+	}
+	l.rankings[rankingType] = rank
+	return rank, nil
+}
+
+// containing returns the ranking types that contain the game info
+func (l *rankingLoader) containing(id primitive.ObjectID) ([]string, error) {
+	var res []string
+	for _, rankingType := range crawler.Steam250RankingTypes {
+		rank, err := l.load(rankingType)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(rank, id) {
+			res = append(res, rankingType)
+		}
+	}
+	return res, nil
+}
+
+// authorGamesLoader batches the game item pages of authors.
+// Resolvers returning authors prime their names, the first load of a page then
+// fetches that page for every primed author at once instead of once per author.
+type authorGamesLoader struct {
+	mu     sync.Mutex
+	names  map[string]struct{}
+	loaded map[authorGamesKey]map[string]*db.AuthorGameItems
+}
+
+// authorGamesKey is a page of the games of authors, authors in one query share it
+type authorGamesKey struct {
+	first int32
+	after string
+}
+
+func newAuthorGamesLoader() *authorGamesLoader {
+	return &authorGamesLoader{
+		names:  make(map[string]struct{}),
+		loaded: make(map[authorGamesKey]map[string]*db.AuthorGameItems),
+	}
+}
+
+// prime queues authors for the next batch
+func (l *authorGamesLoader) prime(names ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, name := range names {
+		l.names[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+	}
+}
+
+// load returns the page of the games of an author
+func (l *authorGamesLoader) load(name string, args pageArgs) (*db.AuthorGameItems, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	key := authorGamesKey{first: args.First}
+	if args.After != nil {
+		key.after = *args.After
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if items, ok := l.loaded[key][name]; ok {
+		return items, nil
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	l.names[name] = struct{}{}
+	batch := make([]string, 0, len(l.names))
+	for n := range l.names {
+		if _, ok := l.loaded[key][n]; !ok {
+			batch = append(batch, n)
+		}
+	}
+	items, err := db.GetGameItemsByAuthorsPage(batch, page)
+	if err != nil {
+		return nil, err
+	}
+	if l.loaded[key] == nil {
+		l.loaded[key] = make(map[string]*db.AuthorGameItems)
+	}
+	for n, page := range items {
+		l.loaded[key][n] = page
+	}
+	return l.loaded[key][name], nil
+}
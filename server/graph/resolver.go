@@ -0,0 +1,281 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxBatchKeys    = 100
+	maxSuggestLimit = 20
+)
+
+type queryResolver struct{}
+
+func (q *queryResolver) Game(ctx context.Context, args struct{ ID graphql.ID }) (*gameInfoResolver, error) {
+	id, err := primitive.ObjectIDFromHex(string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	info, err := db.GetGameInfoByID(id, nil)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return newGameInfoResolver(ctx, info), nil
+}
+
+func (q *queryResolver) GameByPlatform(ctx context.Context, args struct {
+	Platform string
+	ID       int32
+}) (*gameInfoResolver, error) {
+	info, err := db.GetGameInfoByPlatformID(args.Platform, int(args.ID), nil)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return newGameInfoResolver(ctx, info), nil
+}
+
+func (q *queryResolver) Games(ctx context.Context, args struct {
+	IDs      *[]graphql.ID
+	SteamIDs *[]int32
+	IgdbIDs  *[]int32
+}) ([]*gameInfoResolver, error) {
+	var ids []primitive.ObjectID
+	var steamIDs, igdbIDs []int
+	if args.IDs != nil {
+		for _, id := range *args.IDs {
+			objID, err := primitive.ObjectIDFromHex(string(id))
+			if err != nil {
+				return nil, fmt.Errorf("invalid ID: %s", id)
+			}
+			ids = append(ids, objID)
+		}
+	}
+	if args.SteamIDs != nil {
+		steamIDs = toInts(*args.SteamIDs)
+	}
+	if args.IgdbIDs != nil {
+		igdbIDs = toInts(*args.IgdbIDs)
+	}
+	for _, id := range steamIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid Steam ID: %d", id)
+		}
+	}
+	for _, id := range igdbIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid IGDB ID: %d", id)
+		}
+	}
+	if keys := len(ids) + len(steamIDs) + len(igdbIDs); keys == 0 || keys > maxBatchKeys {
+		return nil, fmt.Errorf("between 1 and %d keys are required", maxBatchKeys)
+	}
+	infos, err := db.GetGameInfosByKeys(ids, steamIDs, igdbIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newGameInfoResolvers(ctx, infos), nil
+}
+
+func (q *queryResolver) GameItem(args struct{ ID graphql.ID }) (*gameItemResolver, error) {
+	id, err := primitive.ObjectIDFromHex(string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	item, err := db.GetGameItemByID(id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &gameItemResolver{item: item}, nil
+}
+
+func (q *queryResolver) GameItemsByRawName(args struct{ Name string }) ([]*gameItemResolver, error) {
+	items, err := db.GetGameItemByRawName(args.Name)
+	if err != nil {
+		return nil, err
+	}
+	return newGameItemResolvers(items), nil
+}
+
+func (q *queryResolver) Unorganized(args pageArgs) (*gameItemConnection, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	items, next, total, err := db.GetUnorganizedGameItemsPage(page)
+	if err != nil {
+		return nil, err
+	}
+	return &gameItemConnection{nodes: newGameItemResolvers(items), next: next, total: total}, nil
+}
+
+type searchFilterInput struct {
+	Author       *string
+	Developer    *string
+	Publisher    *string
+	Language     *string
+	HasSteamID   *bool
+	MinSize      *string
+	MaxSize      *string
+	UpdatedSince *graphql.Time
+}
+
+func (in *searchFilterInput) filter() (*model.SearchFilter, error) {
+	filter := &model.SearchFilter{}
+	if in == nil {
+		return filter, nil
+	}
+	filter.Author = deref(in.Author)
+	filter.Developer = deref(in.Developer)
+	filter.Publisher = deref(in.Publisher)
+	filter.Language = deref(in.Language)
+	filter.HasSteamID = in.HasSteamID
+	var err error
+	if in.MinSize != nil {
+		if filter.MinSize, err = utils.ParseSize(*in.MinSize); err != nil {
+			return nil, err
+		}
+	}
+	if in.MaxSize != nil {
+		if filter.MaxSize, err = utils.ParseSize(*in.MaxSize); err != nil {
+			return nil, err
+		}
+	}
+	if in.UpdatedSince != nil {
+		filter.UpdatedSince = in.UpdatedSince.Time
+	}
+	return filter, nil
+}
+
+func (q *queryResolver) Search(ctx context.Context, args struct {
+	Keyword string
+	Filter  *searchFilterInput
+	pageArgs
+}) (*searchResult, error) {
+	filter, err := args.Filter.filter()
+	if err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(args.Keyword) > 64 {
+		return nil, errors.New("keyword must be at most 64 characters")
+	}
+	if filter.IsEmpty() && utf8.RuneCountInString(args.Keyword) < 4 {
+		return nil, errors.New("keyword must be at least 4 characters when no filter is given")
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	infos, next, total, _, err := db.SearchGameInfosCache(args.Keyword, filter, page, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return &searchResult{
+		gameInfoConnection: gameInfoConnection{nodes: newGameInfoResolvers(ctx, infos), next: next, total: total},
+		keyword:            args.Keyword,
+		filter:             filter,
+		page:               page,
+	}, nil
+}
+
+func (q *queryResolver) Suggest(args struct {
+	Query string
+	Limit int32
+}) ([]*gameSuggestionResolver, error) {
+	limit := int(args.Limit)
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+	matches, err := db.FuzzySearchGameInfoNames(args.Query, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gameSuggestionResolver, 0, len(matches))
+	for _, match := range matches {
+		res = append(res, &gameSuggestionResolver{
+			id:      graphql.ID(match.Key.Hex()),
+			name:    match.Name,
+			matched: match.Matched,
+			score:   match.Score,
+		})
+	}
+	return res, nil
+}
+
+func (q *queryResolver) Ranking(ctx context.Context, args struct {
+	Type string
+	pageArgs
+}) (*gameInfoConnection, error) {
+	if !slices.Contains(crawler.Steam250RankingTypes, args.Type) {
+		return nil, fmt.Errorf("invalid ranking type: %s", args.Type)
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	rank, err := loadersFrom(ctx).rankings.load(args.Type)
+	if err != nil {
+		return nil, err
+	}
+	ids, next := db.PaginateSlice(rank, page)
+	infos, err := db.GetGameInfosByIDs(ids, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &gameInfoConnection{nodes: newGameInfoResolvers(ctx, infos), next: next, total: int64(len(rank))}, nil
+}
+
+func (q *queryResolver) Authors(ctx context.Context, args pageArgs) (*authorConnection, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	authors, next, total, err := db.GetAuthorsPage(page)
+	if err != nil {
+		return nil, err
+	}
+	return &authorConnection{nodes: newAuthorResolvers(ctx, authors), next: next, total: total}, nil
+}
+
+func (q *queryResolver) Author(args struct{ Name string }) *authorResolver {
+	return &authorResolver{name: args.Name}
+}
+
+func toInts(values []int32) []int {
+	res := make([]int, 0, len(values))
+	for _, v := range values {
+		res = append(res, int(v))
+	}
+	return res
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type gameInfoResolver struct {
+	info *model.GameInfo
+}
+
+// newGameInfoResolvers wraps infos and primes the loader with all of their items,
+// so resolving games on the whole list costs at most one query
+func newGameInfoResolvers(ctx context.Context, infos []*model.GameInfo) []*gameInfoResolver {
+	loader := loadersFrom(ctx).gameItems
+	res := make([]*gameInfoResolver, 0, len(infos))
+	for _, info := range infos {
+		loader.seed(info.Games...)
+		loader.prime(info.GameIDs...)
+		res = append(res, &gameInfoResolver{info: info})
+	}
+	return res
+}
+
+func newGameInfoResolver(ctx context.Context, info *model.GameInfo) *gameInfoResolver {
+	return newGameInfoResolvers(ctx, []*model.GameInfo{info})[0]
+}
+
+func (r *gameInfoResolver) ID() graphql.ID          { return graphql.ID(r.info.ID.Hex()) }
+func (r *gameInfoResolver) Name() string            { return r.info.Name }
+func (r *gameInfoResolver) Description() string     { return r.info.Description }
+func (r *gameInfoResolver) Aliases() []string       { return nonNil(r.info.Aliases) }
+func (r *gameInfoResolver) Developers() []string    { return nonNil(r.info.Developers) }
+func (r *gameInfoResolver) Publishers() []string    { return nonNil(r.info.Publishers) }
+func (r *gameInfoResolver) IgdbID() int32           { return int32(r.info.IGDBID) }
+func (r *gameInfoResolver) SteamID() int32          { return int32(r.info.SteamID) }
+func (r *gameInfoResolver) Cover() string           { return r.info.Cover }
+func (r *gameInfoResolver) Languages() []string     { return nonNil(r.info.Languages) }
+func (r *gameInfoResolver) Screenshots() []string   { return nonNil(r.info.Screenshots) }
+func (r *gameInfoResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.info.CreatedAt} }
+func (r *gameInfoResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.info.UpdatedAt} }
+
+func (r *gameInfoResolver) Games(ctx context.Context, args struct{ Author *string }) ([]*gameItemResolver, error) {
+	items, err := loadersFrom(ctx).gameItems.loadMany(r.info.GameIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*gameItemResolver, 0, len(items))
+	for _, item := range items {
+		if args.Author != nil && !strings.EqualFold(item.Author, *args.Author) {
+			continue
+		}
+		res = append(res, &gameItemResolver{item: item})
+	}
+	return res, nil
+}
+
+func (r *gameInfoResolver) Rankings(ctx context.Context) ([]string, error) {
+	rankings, err := loadersFrom(ctx).rankings.containing(r.info.ID)
+	return nonNil(rankings), err
+}
+
+type gameItemResolver struct {
+	item *model.GameItem
+}
+
+func newGameItemResolvers(items []*model.GameItem) []*gameItemResolver {
+	res := make([]*gameItemResolver, 0, len(items))
+	for _, item := range items {
+		res = append(res, &gameItemResolver{item: item})
+	}
+	return res
+}
+
+func (r *gameItemResolver) ID() graphql.ID          { return graphql.ID(r.item.ID.Hex()) }
+func (r *gameItemResolver) Name() string            { return r.item.Name }
+func (r *gameItemResolver) RawName() string         { return r.item.RawName }
+func (r *gameItemResolver) DownloadLink() string    { return r.item.Download }
+func (r *gameItemResolver) Size() string            { return r.item.Size }
+func (r *gameItemResolver) Url() string             { return r.item.Url }
+func (r *gameItemResolver) Password() string        { return r.item.Password }
+func (r *gameItemResolver) Author() string          { return r.item.Author }
+func (r *gameItemResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.item.CreatedAt} }
+func (r *gameItemResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.item.UpdatedAt} }
+
+type authorResolver struct {
+	name string
+	// nested authors are resolved for a list, their pages are smaller
+	nested bool
+}
+
+// newAuthorResolvers wraps authors of a list and primes the loader with them,
+// so resolving games on the whole list costs two queries
+func newAuthorResolvers(ctx context.Context, names []string) []*authorResolver {
+	loadersFrom(ctx).authorGames.prime(names...)
+	res := make([]*authorResolver, 0, len(names))
+	for _, name := range names {
+		res = append(res, &authorResolver{name: name, nested: true})
+	}
+	return res
+}
+
+func (r *authorResolver) Name() string { return r.name }
+
+func (r *authorResolver) Games(ctx context.Context, args pageArgs) (*gameItemConnection, error) {
+	if r.nested && args.First > maxNestedPageSize {
+		args.First = maxNestedPageSize
+	}
+	page, err := loadersFrom(ctx).authorGames.load(r.name, args)
+	if err != nil {
+		return nil, err
+	}
+	return &gameItemConnection{nodes: newGameItemResolvers(page.Items), next: page.Next, total: page.Total}, nil
+}
+
+type gameSuggestionResolver struct {
+	id      graphql.ID
+	name    string
+	matched string
+	score   float64
+}
+
+func (r *gameSuggestionResolver) ID() graphql.ID  { return r.id }
+func (r *gameSuggestionResolver) Name() string    { return r.name }
+func (r *gameSuggestionResolver) Matched() string { return r.matched }
+func (r *gameSuggestionResolver) Score() float64  { return r.score }
+
+type pageArgs struct {
+	First int32
+	After *string
+}
+
+const (
+	maxPageSize = 50
+	// maxNestedPageSize caps the pages of lists inside list items,
+	// which multiply with the size of the outer page
+	maxNestedPageSize = 20
+)
+
+func (args pageArgs) page() (*db.Page, error) {
+	limit := 10
+	if args.First > 0 {
+		limit = int(args.First)
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	cursor := ""
+	if args.After != nil {
+		cursor = *args.After
+	}
+	return db.NewPage(cursor, 0, limit)
+}
+
+type gameInfoConnection struct {
+	nodes []*gameInfoResolver
+	next  string
+	total int64
+}
+
+func (c *gameInfoConnection) Nodes() []*gameInfoResolver { return c.nodes }
+func (c *gameInfoConnection) NextCursor() *string        { return nextCursor(c.next) }
+func (c *gameInfoConnection) Total() int32               { return int32(c.total) }
+
+type gameItemConnection struct {
+	nodes []*gameItemResolver
+	next  string
+	total int64
+}
+
+func (c *gameItemConnection) Nodes() []*gameItemResolver { return c.nodes }
+func (c *gameItemConnection) NextCursor() *string        { return nextCursor(c.next) }
+func (c *gameItemConnection) Total() int32               { return int32(c.total) }
+
+type authorConnection struct {
+	nodes []*authorResolver
+	next  string
+	total int64
+}
+
+func (c *authorConnection) Nodes() []*authorResolver { return c.nodes }
+func (c *authorConnection) NextCursor() *string      { return nextCursor(c.next) }
+func (c *authorConnection) Total() int32             { return int32(c.total) }
+
+// searchResult counts the facets only when they are selected
+type searchResult struct {
+	gameInfoConnection
+	keyword string
+	filter  *model.SearchFilter
+	page    *db.Page
+}
+
+func (r *searchResult) Facets() (*searchFacetsResolver, error) {
+	_, _, _, facets, err := db.SearchGameInfosCache(r.keyword, r.filter, r.page, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if facets == nil {
+		return nil, nil
+	}
+	return &searchFacetsResolver{facets: facets}, nil
+}
+
+type searchFacetsResolver struct {
+	facets *model.SearchFacets
+}
+
+func (r *searchFacetsResolver) Authors() []*facetCountResolver { return facetCounts(r.facets.Authors) }
+func (r *searchFacetsResolver) Developers() []*facetCountResolver {
+	return facetCounts(r.facets.Developers)
+}
+func (r *searchFacetsResolver) Publishers() []*facetCountResolver {
+	return facetCounts(r.facets.Publishers)
+}
+func (r *searchFacetsResolver) Languages() []*facetCountResolver {
+	return facetCounts(r.facets.Languages)
+}
+func (r *searchFacetsResolver) HasSteamID() []*facetCountResolver {
+	return facetCounts(r.facets.HasSteamID)
+}
+
+type facetCountResolver struct {
+	count model.FacetCount
+}
+
+func (r *facetCountResolver) Value() string { return r.count.Value }
+func (r *facetCountResolver) Count() int32  { return int32(r.count.Count) }
+
+func facetCounts(counts []model.FacetCount) []*facetCountResolver {
+	res := make([]*facetCountResolver, 0, len(counts))
+	for _, count := range counts {
+		res = append(res, &facetCountResolver{count: count})
+	}
+	return res
+}
+
+func nextCursor(next string) *string {
+	if next == "" {
+		return nil
+	}
+	return &next
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
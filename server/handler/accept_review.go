@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReviewIDRequest struct {
+	ID string `uri:"id" json:"id" binding:"required"`
+}
+
+// AcceptReviewRequest picks the candidate at index Candidate, the best one by default,
+// or any other game with Platform and PlatformID
+type AcceptReviewRequest struct {
+	Candidate  int    `json:"candidate"`
+	Platform   string `json:"platform" binding:"omitempty,oneof=igdb steam"`
+	PlatformID int    `json:"platform_id"`
+}
+
+type ReviewResponse struct {
+	Status   string             `json:"status"`
+	Message  string             `json:"message,omitempty"`
+	Review   *model.MatchReview `json:"review,omitempty"`
+	GameInfo *model.GameInfo    `json:"game_info,omitempty"`
+}
+
+// AcceptReviewHandler links a queued game item to a candidate
+// @Summary Accept a review
+// @Description Link the game item of a pending review to one of its candidates, or to another IGDB or Steam game
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Review ID"
+// @Param body body AcceptReviewRequest false "Chosen game"
+// @Success 200 {object} ReviewResponse
+// @Failure 400 {object} ReviewResponse
+// @Failure 404 {object} ReviewResponse
+// @Failure 409 {object} ReviewResponse
+// @Failure 500 {object} ReviewResponse
+// @Security BearerAuth
+// @Router /review/{id}/accept [post]
+func AcceptReviewHandler(c *gin.Context) {
+	review, ok := bindReview(c)
+	if !ok {
+		return
+	}
+	var req AcceptReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ReviewResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	var chosen model.MatchCandidate
+	switch {
+	case req.Platform != "" && req.PlatformID > 0:
+		chosen = model.MatchCandidate{Platform: req.Platform, PlatformID: req.PlatformID}
+	case req.Platform != "" || req.PlatformID != 0:
+		c.JSON(http.StatusBadRequest, ReviewResponse{
+			Status:  "error",
+			Message: "platform and platform_id must be given together",
+		})
+		return
+	case req.Candidate >= 0 && req.Candidate < len(review.Candidates):
+		chosen = review.Candidates[req.Candidate]
+	default:
+		c.JSON(http.StatusBadRequest, ReviewResponse{
+			Status:  "error",
+			Message: "Invalid candidate",
+		})
+		return
+	}
+	info, err := crawler.AcceptMatchReview(review, chosen, middleware.Owner(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ReviewResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, ReviewResponse{
+		Status:   "ok",
+		Message:  "Review accepted successfully",
+		Review:   review,
+		GameInfo: info,
+	})
+}
+
+// bindReview returns the pending review of the request, or writes the error response
+func bindReview(c *gin.Context) (*model.MatchReview, bool) {
+	var uri ReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, ReviewResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	id, err := primitive.ObjectIDFromHex(uri.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ReviewResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return nil, false
+	}
+	review, err := db.GetMatchReviewByID(id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, ReviewResponse{
+				Status:  "error",
+				Message: "Review not found",
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, ReviewResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	if review.Status != model.MatchReviewPending {
+		c.JSON(http.StatusConflict, ReviewResponse{
+			Status:  "error",
+			Message: "Review is already " + review.Status,
+			Review:  review,
+		})
+		return nil, false
+	}
+	return review, true
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/notify"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AddWatchlistEntryRequest watches one of SteamID, IGDBID, GameInfoID or Name
+type AddWatchlistEntryRequest struct {
+	SteamID    int    `json:"steam_id"`
+	IGDBID     int    `json:"igdb_id"`
+	GameInfoID string `json:"game_info_id"`
+	// Name matches game infos whose name or alias contains it
+	Name string `json:"name"`
+	// Authors to watch, all authors when empty
+	Authors []string `json:"authors"`
+	// NotifierID is the notifier told when an item of the game is linked or updated
+	NotifierID string `json:"notifier_id" binding:"required"`
+}
+
+type AddWatchlistEntryResponse struct {
+	Status  string                `json:"status"`
+	Message string                `json:"message,omitempty"`
+	Entry   *model.WatchlistEntry `json:"entry,omitempty"`
+}
+
+// AddWatchlistEntryHandler adds a game to the watchlist of the API key owner
+// @Summary Watch a game
+// @Description Notifies the given notifier when a game item of the game is linked to its game info, or when an organized item of it is updated
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param body body AddWatchlistEntryRequest true "Watched game"
+// @Success 200 {object} AddWatchlistEntryResponse
+// @Failure 400 {object} AddWatchlistEntryResponse
+// @Failure 500 {object} AddWatchlistEntryResponse
+// @Security BearerAuth
+// @Router /watchlist [post]
+func AddWatchlistEntryHandler(c *gin.Context) {
+	var req AddWatchlistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AddWatchlistEntryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	entry := &model.WatchlistEntry{
+		Owner:   middleware.Owner(c),
+		SteamID: req.SteamID,
+		IGDBID:  req.IGDBID,
+		Name:    strings.TrimSpace(req.Name),
+		Authors: nonNil(req.Authors),
+	}
+	keys := 0
+	if req.SteamID != 0 {
+		keys++
+	}
+	if req.IGDBID != 0 {
+		keys++
+	}
+	if req.GameInfoID != "" {
+		keys++
+		id, err := primitive.ObjectIDFromHex(req.GameInfoID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, AddWatchlistEntryResponse{
+				Status:  "error",
+				Message: "Invalid game info ID",
+			})
+			return
+		}
+		entry.GameInfoID = id
+	}
+	if entry.Name != "" {
+		keys++
+	}
+	if keys != 1 {
+		c.JSON(http.StatusBadRequest, AddWatchlistEntryResponse{
+			Status:  "error",
+			Message: "Exactly one of steam_id, igdb_id, game_info_id and name is required",
+		})
+		return
+	}
+	notifierID, err := primitive.ObjectIDFromHex(req.NotifierID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, AddWatchlistEntryResponse{
+			Status:  "error",
+			Message: "Invalid notifier ID",
+		})
+		return
+	}
+	notifier, err := db.GetNotifierByID(notifierID)
+	if err == nil && notifier.Owner != entry.Owner && !middleware.IsAdmin(c) {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusBadRequest, AddWatchlistEntryResponse{
+				Status:  "error",
+				Message: "Notifier not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, AddWatchlistEntryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	entry.NotifierID = notifierID
+	if err := db.SaveWatchlistEntry(entry); err != nil {
+		c.JSON(http.StatusInternalServerError, AddWatchlistEntryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notify.InvalidateWatchlist()
+	c.JSON(http.StatusOK, AddWatchlistEntryResponse{
+		Status: "ok",
+		Entry:  entry,
+	})
+}
@@ -9,7 +9,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+type CleanGameResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// CleanGameHandler removes invalid game items and infos.
+// @Summary Clean games
+// @Description Removes invalid game items and game infos without items
+// @Tags game
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Success 200 {object} CleanGameResponse
+// @Failure 401 {object} CleanGameResponse
+// @Security BearerAuth
+// @Router /clean [post]
 func CleanGameHandler(ctx *gin.Context) {
 	task.Clean(log.TaskLogger)
-	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	ctx.JSON(http.StatusOK, CleanGameResponse{Status: "ok"})
 }
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/crawler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClearNegativeCacheResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// ClearNegativeCacheHandler drops the cached failed ID lookups of a name
+// @Summary Clear failed ID lookups
+// @Description Forget that a game name did not resolve to an IGDB or Steam ID, so that the next organize looks it up again
+// @Tags cache
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param name query string true "Game name as crawled"
+// @Success 200 {object} ClearNegativeCacheResponse
+// @Failure 400 {object} ClearNegativeCacheResponse
+// @Failure 500 {object} ClearNegativeCacheResponse
+// @Security BearerAuth
+// @Router /cache/negative [delete]
+func ClearNegativeCacheHandler(c *gin.Context) {
+	var req NegativeCacheRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ClearNegativeCacheResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := crawler.ClearNegativeEntries(req.Name); err != nil {
+		c.JSON(http.StatusInternalServerError, ClearNegativeCacheResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, ClearNegativeCacheResponse{
+		Status:  "ok",
+		Message: "Negative cache cleared successfully",
+	})
+}
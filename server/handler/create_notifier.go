@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/notify"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateNotifierRequest struct {
+	Name string `json:"name"`
+	// Type is one of telegram, discord or matrix, only the settings of the type are used
+	Type     string                  `json:"type" binding:"required"`
+	Telegram *model.TelegramSettings `json:"telegram"`
+	Discord  *model.DiscordSettings  `json:"discord"`
+	Matrix   *model.MatrixSettings   `json:"matrix"`
+	// Authors to notify about, all authors when empty
+	Authors []string `json:"authors"`
+	// GameInfoIDs to notify about, all games when empty
+	GameInfoIDs []string `json:"game_info_ids"`
+	// WatchlistOnly notifies only about the items of the watchlist entries using the notifier
+	WatchlistOnly bool `json:"watchlist_only"`
+}
+
+type NotifierResponse struct {
+	Status   string          `json:"status"`
+	Message  string          `json:"message,omitempty"`
+	Notifier *model.Notifier `json:"notifier,omitempty"`
+}
+
+// CreateNotifierHandler creates a chat notifier
+// @Summary Create a notifier
+// @Description Sends a Telegram, Discord or Matrix message when an item is linked to a game info or an organized item is updated
+// @Tags notifier
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param body body CreateNotifierRequest true "Notifier"
+// @Success 200 {object} NotifierResponse
+// @Failure 400 {object} NotifierResponse
+// @Failure 500 {object} NotifierResponse
+// @Security BearerAuth
+// @Router /notifier [post]
+func CreateNotifierHandler(c *gin.Context) {
+	var req CreateNotifierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	gameInfoIDs, err := parseObjectIDs(req.GameInfoIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notifier := &model.Notifier{
+		Owner:         middleware.Owner(c),
+		Name:          req.Name,
+		Type:          req.Type,
+		Telegram:      req.Telegram,
+		Discord:       req.Discord,
+		Matrix:        req.Matrix,
+		Authors:       nonNil(req.Authors),
+		GameInfoIDs:   gameInfoIDs,
+		WatchlistOnly: req.WatchlistOnly,
+		Active:        true,
+	}
+	if err := notify.Validate(c.Request.Context(), notifier); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := db.SaveNotifier(notifier); err != nil {
+		c.JSON(http.StatusInternalServerError, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notify.Invalidate()
+	notifier.Redact()
+	c.JSON(http.StatusOK, NotifierResponse{
+		Status:   "ok",
+		Notifier: notifier,
+	})
+}
+
+func parseObjectIDs(ids []string) ([]primitive.ObjectID, error) {
+	res := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID: %s", id)
+		}
+		res = append(res, objID)
+	}
+	return res, nil
+}
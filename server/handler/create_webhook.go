@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/events"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateWebhookRequest struct {
+	URL string `json:"url" binding:"required"`
+	// Events to deliver, all events when empty
+	Events []string `json:"events"`
+	// Authors to deliver item events for, all authors when empty
+	Authors []string `json:"authors"`
+}
+
+type WebhookResponse struct {
+	Status  string         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Webhook *model.Webhook `json:"webhook,omitempty"`
+}
+
+// CreateWebhookHandler creates a webhook subscription
+// @Summary Create a webhook
+// @Description Subscribes a URL to events. The returned secret signs every delivery and is only shown once.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param body body CreateWebhookRequest true "Webhook"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {object} WebhookResponse
+// @Failure 500 {object} WebhookResponse
+// @Security BearerAuth
+// @Router /webhook [post]
+func CreateWebhookHandler(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := validateWebhook(req.URL, req.Events); err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	hook := &model.Webhook{
+		URL:     req.URL,
+		Secret:  secret,
+		Events:  nonNil(req.Events),
+		Authors: nonNil(req.Authors),
+		Active:  true,
+	}
+	if err := db.SaveWebhook(hook); err != nil {
+		c.JSON(http.StatusInternalServerError, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	webhook.Invalidate()
+	c.JSON(http.StatusOK, WebhookResponse{
+		Status:  "ok",
+		Webhook: hook,
+	})
+}
+
+func validateWebhook(rawURL string, types []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url: %s", rawURL)
+	}
+	for _, t := range types {
+		if !slices.Contains(events.Types, events.Type(t)) {
+			return fmt.Errorf("unknown event type: %s", t)
+		}
+	}
+	return nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/db"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DeleteAliasRequest struct {
+	Name string `form:"name" json:"name" binding:"required"`
+}
+
+type DeleteAliasResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// DeleteAliasHandler forgets the alias of a name
+// @Summary Delete a name alias
+// @Description Forget a wrong alias, items with the name are matched by searching again
+// @Tags alias
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param name query string true "Game name, it is normalized like the names of game items"
+// @Success 200 {object} DeleteAliasResponse
+// @Failure 400 {object} DeleteAliasResponse
+// @Failure 404 {object} DeleteAliasResponse
+// @Failure 500 {object} DeleteAliasResponse
+// @Security BearerAuth
+// @Router /alias [delete]
+func DeleteAliasHandler(c *gin.Context) {
+	var req DeleteAliasRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, DeleteAliasResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := db.DeleteNameAlias(crawler.AliasName(req.Name)); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, DeleteAliasResponse{
+				Status:  "error",
+				Message: "Alias not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, DeleteAliasResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, DeleteAliasResponse{
+		Status:  "ok",
+		Message: "Alias deleted successfully",
+	})
+}
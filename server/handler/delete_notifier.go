@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/notify"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DeleteNotifierHandler deletes a chat notifier
+// @Summary Delete a notifier
+// @Description Delete a notifier
+// @Tags notifier
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Notifier ID"
+// @Success 200 {object} NotifierResponse
+// @Failure 400 {object} NotifierResponse
+// @Failure 404 {object} NotifierResponse
+// @Failure 500 {object} NotifierResponse
+// @Security BearerAuth
+// @Router /notifier/{id} [delete]
+func DeleteNotifierHandler(c *gin.Context) {
+	var req NotifierIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	notifier, err := db.GetNotifierByID(id)
+	if err == nil && !middleware.CanManage(c, notifier.Owner) {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil {
+		err = db.DeleteNotifierByID(id)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, NotifierResponse{
+				Status:  "error",
+				Message: "Notifier not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notify.Invalidate()
+	c.JSON(http.StatusOK, NotifierResponse{
+		Status:  "ok",
+		Message: "Notifier deleted successfully",
+	})
+}
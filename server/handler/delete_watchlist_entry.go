@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/notify"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DeleteWatchlistEntryRequest struct {
+	ID string `uri:"id" json:"id" binding:"required"`
+}
+
+type DeleteWatchlistEntryResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// DeleteWatchlistEntryHandler removes a game from the watchlist of the API key owner
+// @Summary Unwatch a game
+// @Description Remove an entry from the watchlist of the owner of the API key
+// @Tags watchlist
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Watchlist entry ID"
+// @Success 200 {object} DeleteWatchlistEntryResponse
+// @Failure 400 {object} DeleteWatchlistEntryResponse
+// @Failure 404 {object} DeleteWatchlistEntryResponse
+// @Failure 500 {object} DeleteWatchlistEntryResponse
+// @Security BearerAuth
+// @Router /watchlist/{id} [delete]
+func DeleteWatchlistEntryHandler(c *gin.Context) {
+	var req DeleteWatchlistEntryRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, DeleteWatchlistEntryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, DeleteWatchlistEntryResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	if err := db.DeleteWatchlistEntry(middleware.Owner(c), id); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, DeleteWatchlistEntryResponse{
+				Status:  "error",
+				Message: "Watchlist entry not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, DeleteWatchlistEntryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notify.InvalidateWatchlist()
+	c.JSON(http.StatusOK, DeleteWatchlistEntryResponse{
+		Status:  "ok",
+		Message: "Watchlist entry deleted successfully",
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/webhook"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DeleteWebhookHandler deletes a webhook subscription
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhook
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {object} WebhookResponse
+// @Failure 404 {object} WebhookResponse
+// @Failure 500 {object} WebhookResponse
+// @Security BearerAuth
+// @Router /webhook/{id} [delete]
+func DeleteWebhookHandler(c *gin.Context) {
+	var req WebhookIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	if err := db.DeleteWebhookByID(id); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, WebhookResponse{
+				Status:  "error",
+				Message: "Webhook not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	webhook.Invalidate()
+	c.JSON(http.StatusOK, WebhookResponse{
+		Status:  "ok",
+		Message: "Webhook deleted successfully",
+	})
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type EventsRequest struct {
+	Types       string `form:"types" json:"types"`
+	LastEventID string `form:"last_event_id" json:"last_event_id"`
+}
+
+const eventsPingInterval = 30 * time.Second
+
+var eventsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// EventsHandler streams events as Server-Sent Events.
+// @Summary Event stream
+// @Description Streams item-created, item-updated, info-organized, crawl-started, crawl-finished, source-failed and clean-finished events as Server-Sent Events.
+// @Description Reconnecting clients resume from the Last-Event-ID header while the events are still buffered.
+// @Tags events
+// @Produce text/event-stream
+// @Param types query string false "Comma separated event types, all types by default"
+// @Param last_event_id query string false "Resume after this event ID, same as the Last-Event-ID header"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Router /events [get]
+func EventsHandler(c *gin.Context) {
+	sub, ok := subscribeEvents(c)
+	if !ok {
+		return
+	}
+	defer events.Unsubscribe(sub)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Writer.Flush()
+	ticker := time.NewTicker(eventsPingInterval)
+	defer ticker.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case e, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				return true
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+			return err == nil
+		case <-ticker.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+// EventsWebSocketHandler streams events over a WebSocket.
+// @Summary Event stream over WebSocket
+// @Description Streams the same events as /events over a WebSocket, one JSON message per event
+// @Tags events
+// @Param types query string false "Comma separated event types, all types by default"
+// @Param last_event_id query string false "Resume after this event ID"
+// @Success 101 {string} string
+// @Failure 400 {string} string
+// @Router /events/ws [get]
+func EventsWebSocketHandler(c *gin.Context) {
+	sub, ok := subscribeEvents(c)
+	if !ok {
+		return
+	}
+	defer events.Unsubscribe(sub)
+	conn, err := eventsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	closed := make(chan struct{})
+	go func() {
+		// the client only sends control frames, reading detects when it goes away
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	ticker := time.NewTicker(eventsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func subscribeEvents(c *gin.Context) (*events.Subscription, bool) {
+	var req EventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	var types []events.Type
+	if req.Types != "" {
+		for _, t := range strings.Split(req.Types, ",") {
+			t := events.Type(strings.TrimSpace(t))
+			if !slices.Contains(events.Types, t) {
+				c.String(http.StatusBadRequest, fmt.Sprintf("Unknown event type: %s", t))
+				return nil, false
+			}
+			types = append(types, t)
+		}
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = req.LastEventID
+	}
+	var lastID uint64
+	if lastEventID != "" {
+		var err error
+		if lastID, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			c.String(http.StatusBadRequest, "Invalid last event ID")
+			return nil, false
+		}
+	}
+	return events.Subscribe(lastID, types...), true
+}
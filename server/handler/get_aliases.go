@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetAliasesResponse struct {
+	Status  string             `json:"status"`
+	Message string             `json:"message,omitempty"`
+	Aliases []*model.NameAlias `json:"aliases"`
+}
+
+// GetAliasesHandler exports the alias table
+// @Summary Export name aliases
+// @Description Export the aliases learned from manual organizing, the response body can be imported by another instance
+// @Tags alias
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Success 200 {object} GetAliasesResponse
+// @Failure 500 {object} GetAliasesResponse
+// @Security BearerAuth
+// @Router /alias [get]
+func GetAliasesHandler(c *gin.Context) {
+	aliases, err := db.GetAllNameAliases()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetAliasesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, GetAliasesResponse{
+		Status:  "ok",
+		Aliases: aliases,
+	})
+}
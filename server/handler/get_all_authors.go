@@ -8,12 +8,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+type GetAllAuthorsRequest struct {
+	PaginationRequest
+}
+
 type GetAllAuthorsResponse struct {
 	Status  string   `json:"status"`
 	Message string   `json:"message,omitempty"`
 	Authors []string `json:"authors,omitempty"`
 }
 
+type GetAllAuthorsListResponse = ListResponse[string]
+
 // GetAllAuthorsHandler returns all authors
 // @Summary Get all authors
 // @Description Get all authors
@@ -44,3 +50,32 @@ func GetAllAuthorsHandler(ctx *gin.Context) {
 		Authors: authors,
 	})
 }
+
+// GetAllAuthorsListHandler returns a page of the authors in the list envelope
+func GetAllAuthorsListHandler(ctx *gin.Context) {
+	var req GetAllAuthorsRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, GetAllAuthorsListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	page, err := req.page(maxPageLimit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, GetAllAuthorsListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	authors, next, total, err := db.GetAuthorsPage(page)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, GetAllAuthorsListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, newListResponse(authors, next, total))
+}
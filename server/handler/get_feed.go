@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/server/feed"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetFeedRequest struct {
+	Limit int `form:"limit" json:"limit"`
+}
+
+const (
+	defaultFeedLimit = 50
+	maxFeedLimit     = 100
+)
+
+var feedContentTypes = map[string]string{
+	"atom": "application/atom+xml; charset=utf-8",
+	"rss":  "application/rss+xml; charset=utf-8",
+	"json": "application/feed+json; charset=utf-8",
+}
+
+// GetLatestFeedHandler returns the latest crawled or updated game items as a feed.
+// @Summary Latest games feed
+// @Description Returns the most recently crawled or updated game items as an Atom, RSS or JSON feed
+// @Tags feed
+// @Produce xml
+// @Produce json
+// @Param format path string true "Feed format (atom, rss, json)"
+// @Param limit query int false "Number of entries (max 100)"
+// @Success 200 {string} string
+// @Success 304 {string} string
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /feed/latest.{format} [get]
+func GetLatestFeedHandler(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, ok := feedLimit(c)
+		if !ok {
+			return
+		}
+		items, err := db.GetLatestGameItems("", limit)
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeFeed(c, format, "pcgamedb - latest games", items)
+	}
+}
+
+// GetAuthorFeedHandler returns the latest game items of an author as a feed.
+// @Summary Author feed
+// @Description Returns the most recently crawled or updated game items of an author, e.g. /feed/author/fitgirl.atom
+// @Tags feed
+// @Produce xml
+// @Produce json
+// @Param author path string true "Author with the feed format as extension (.atom, .rss, .json)"
+// @Param limit query int false "Number of entries (max 100)"
+// @Success 200 {string} string
+// @Success 304 {string} string
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /feed/author/{author} [get]
+func GetAuthorFeedHandler(c *gin.Context) {
+	author, format, ok := splitFeedFormat(c, c.Param("author"))
+	if !ok {
+		return
+	}
+	limit, ok := feedLimit(c)
+	if !ok {
+		return
+	}
+	items, err := db.GetLatestGameItems(author, limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeFeed(c, format, fmt.Sprintf("pcgamedb - %s", author), items)
+}
+
+// GetGameFeedHandler returns the game items of a game info as a feed.
+// @Summary Game feed
+// @Description Returns the game items organized into a game info, e.g. /feed/game/<id>.atom
+// @Tags feed
+// @Produce xml
+// @Produce json
+// @Param id path string true "Game ID with the feed format as extension (.atom, .rss, .json)"
+// @Success 200 {string} string
+// @Success 304 {string} string
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /feed/game/{id} [get]
+func GetGameFeedHandler(c *gin.Context) {
+	idStr, format, ok := splitFeedFormat(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	info, err := db.GetGameInfoByID(id, nil)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.String(http.StatusNotFound, "Game not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	items, err := db.GetGameItemsByIDs(info.GameIDs)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	slices.SortFunc(items, func(a, b *model.GameItem) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	writeFeed(c, format, fmt.Sprintf("pcgamedb - %s", info.Name), items)
+}
+
+func feedLimit(c *gin.Context) (int, bool) {
+	var req GetFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return 0, false
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultFeedLimit
+	}
+	if req.Limit > maxFeedLimit {
+		req.Limit = maxFeedLimit
+	}
+	return req.Limit, true
+}
+
+// splitFeedFormat splits a path parameter like fitgirl.atom into its name and feed format
+func splitFeedFormat(c *gin.Context, param string) (string, string, bool) {
+	i := strings.LastIndexByte(param, '.')
+	if i > 0 {
+		if _, ok := feedContentTypes[param[i+1:]]; ok {
+			return param[:i], param[i+1:], true
+		}
+	}
+	c.String(http.StatusBadRequest, "Feed format must be one of .atom, .rss or .json")
+	return "", "", false
+}
+
+func writeFeed(c *gin.Context, format string, title string, items []*model.GameItem) {
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	infos, err := db.GetGameInfosByGameItemIDs(ids)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	baseURL := publicURL(c)
+	f := feed.FromGameItems(title, baseURL, baseURL+c.Request.URL.RequestURI(), items, infos)
+	var data []byte
+	switch format {
+	case "atom":
+		data, err = f.Atom()
+	case "rss":
+		data, err = f.RSS()
+	default:
+		data, err = f.JSON()
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeConditional(c, feedContentTypes[format], data, f.Updated)
+}
+
+// publicURL is the configured public URL of the server. Without one, the URL a trusted proxy
+// forwarded the request for is used, the Host and X-Forwarded-* headers of other clients are not,
+// the links then point at the local server.
+func publicURL(c *gin.Context) string {
+	if config.Config.Server.PublicURL != "" {
+		return strings.TrimRight(config.Config.Server.PublicURL, "/")
+	}
+	if !fromTrustedProxy(c) {
+		return "http://localhost:" + config.Config.Server.Port
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := forwardedValue(c.GetHeader("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	host := c.Request.Host
+	if forwarded := forwardedValue(c.GetHeader("X-Forwarded-Host")); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host
+}
+
+// forwardedValue returns the value the first proxy set in a comma separated forwarded header
+func forwardedValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
+
+// fromTrustedProxy reports whether the request was sent by one of server.trusted_proxies
+func fromTrustedProxy(c *gin.Context) bool {
+	remote, err := netip.ParseAddr(c.RemoteIP())
+	if err != nil {
+		return false
+	}
+	remote = remote.Unmap()
+	for _, proxy := range config.Config.Server.TrustedProxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			if prefix.Contains(remote) {
+				return true
+			}
+		} else if addr, err := netip.ParseAddr(proxy); err == nil && addr.Unmap() == remote {
+			return true
+		}
+	}
+	return false
+}
+
+// writeConditional writes data with ETag and Last-Modified headers,
+// answering 304 Not Modified when the client already has it
+func writeConditional(c *gin.Context, contentType string, data []byte, lastModified time.Time) {
+	sum := sha1.Sum(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		t, err := http.ParseTime(since)
+		if err == nil && !lastModified.After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,21 +4,23 @@ import (
 	"net/http"
 
 	"github.com/nitezs/pcgamedb/db"
-	"github.com/nitezs/pcgamedb/model"
 
 	"github.com/gin-gonic/gin"
 )
 
 type GetGameInfosByNameRequest struct {
 	Name string `uri:"name" binding:"required"`
+	ProjectionRequest
 }
 
 type GetGameInfosByNameResponse struct {
-	Status    string            `json:"status"`
-	Message   string            `json:"message,omitempty"`
-	GameInfos []*model.GameInfo `json:"game_infos,omitempty"`
+	Status    string          `json:"status"`
+	Message   string          `json:"message,omitempty"`
+	GameInfos []*GameInfoView `json:"game_infos,omitempty"`
 }
 
+type GetGameInfosByNameListResponse = ListResponse[*GameInfoView]
+
 // GetGameInfosByName retrieves game information by game name.
 // @Summary Retrieve game info by name
 // @Description Retrieves game information details by game name
@@ -26,6 +28,8 @@ type GetGameInfosByNameResponse struct {
 // @Accept json
 // @Produce json
 // @Param name path string true "Game Name"
+// @Param view query string false "Named field set (summary, full)"
+// @Param fields query string false "Comma separated fields to return, overrides view"
 // @Success 200 {object} GetGameInfosByNameResponse
 // @Failure 400 {object} GetGameInfosByNameResponse
 // @Failure 500 {object} GetGameInfosByNameResponse
@@ -39,7 +43,22 @@ func GetGameInfosByNameHandler(c *gin.Context) {
 		})
 		return
 	}
-	games, err := db.GetGameInfosByName(req.Name)
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByNameResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByNameResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	games, err := db.GetGameInfosByName(req.Name, fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetGameInfosByNameResponse{
 			Status:  "error",
@@ -56,6 +75,42 @@ func GetGameInfosByNameHandler(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, GetGameInfosByNameResponse{
 		Status:    "ok",
-		GameInfos: games,
+		GameInfos: newGameInfoViews(games, fields),
 	})
 }
+
+// GetGameInfosByNameListHandler returns the game infos with a name in the list envelope
+func GetGameInfosByNameListHandler(c *gin.Context) {
+	var req GetGameInfosByNameRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByNameListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByNameListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByNameListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	games, err := db.GetGameInfosByName(req.Name, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetGameInfosByNameListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(newGameInfoViews(games, fields), "", int64(len(games))))
+}
@@ -20,6 +20,8 @@ type GetGameItemByRawNameResponse struct {
 	GameItem []*model.GameItem `json:"game_downloads,omitempty"`
 }
 
+type GetGameItemByRawNameListResponse = ListResponse[*model.GameItem]
+
 // GetGameItemByRawName retrieves game download details by raw name.
 // @Summary Retrieve game download by raw name
 // @Description Retrieves details of a game download by its raw name
@@ -67,3 +69,28 @@ func GetGameItemByRawNameHandler(c *gin.Context) {
 		GameItem: gameDownload,
 	})
 }
+
+// GetGameItemByRawNameListHandler returns the game downloads with a raw name in the list envelope
+func GetGameItemByRawNameListHandler(c *gin.Context) {
+	var req GetGameItemByRawNameRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameItemByRawNameListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	gameDownload, err := db.GetGameItemByRawName(req.Name)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusOK, newListResponse[*model.GameItem](nil, "", 0))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, GetGameItemByRawNameListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(gameDownload, "", int64(len(gameDownload))))
+}
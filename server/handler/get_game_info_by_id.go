@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/nitezs/pcgamedb/db"
-	"github.com/nitezs/pcgamedb/model"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,10 +14,14 @@ type GetGameInfoByIDRequest struct {
 	ID string `uri:"id" binding:"required"`
 }
 
+type GetGameInfoByIDQuery struct {
+	ProjectionRequest
+}
+
 type GetGameInfoByIDResponse struct {
-	Status   string          `json:"status"`
-	Message  string          `json:"message,omitempty"`
-	GameInfo *model.GameInfo `json:"game_info,omitempty"`
+	Status   string        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	GameInfo *GameInfoView `json:"game_info,omitempty"`
 }
 
 // GetGameInfoByID retrieves game information by ID.
@@ -28,12 +31,14 @@ type GetGameInfoByIDResponse struct {
 // @Accept json
 // @Produce json
 // @Param id path string true "Game ID"
+// @Param view query string false "Named field set (summary, full)"
+// @Param fields query string false "Comma separated fields to return, overrides view"
 // @Success 200 {object} GetGameInfoByIDResponse
 // @Failure 400 {object} GetGameInfoByIDResponse
 // @Failure 500 {object} GetGameInfoByIDResponse
 // @Router /game/id/{id} [get]
 func GetGameInfoByIDHandler(c *gin.Context) {
-	var req GetGameItemByIDRequest
+	var req GetGameInfoByIDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
 		c.JSON(http.StatusBadRequest, GetGameInfoByIDResponse{
 			Status:  "error",
@@ -41,6 +46,22 @@ func GetGameInfoByIDHandler(c *gin.Context) {
 		})
 		return
 	}
+	var query GetGameInfoByIDQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfoByIDResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	fields, err := query.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfoByIDResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
 	id, err := primitive.ObjectIDFromHex(req.ID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, GetGameInfoByIDResponse{
@@ -49,7 +70,7 @@ func GetGameInfoByIDHandler(c *gin.Context) {
 		})
 		return
 	}
-	gameInfo, err := db.GetGameInfoByID(id)
+	gameInfo, err := db.GetGameInfoByID(id, fields)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusOK, GetGameInfoByIDResponse{
@@ -64,16 +85,18 @@ func GetGameInfoByIDHandler(c *gin.Context) {
 		})
 		return
 	}
-	gameInfo.Games, err = db.GetGameItemsByIDs(gameInfo.GameIDs)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, GetGameInfoByIDResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
-		return
+	if db.NeedGameItems(fields) {
+		gameInfo.Games, err = db.GetGameItemsByIDs(gameInfo.GameIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, GetGameInfoByIDResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 	c.JSON(http.StatusOK, GetGameInfoByIDResponse{
 		Status:   "ok",
-		GameInfo: gameInfo,
+		GameInfo: newGameInfoView(gameInfo, fields),
 	})
 }
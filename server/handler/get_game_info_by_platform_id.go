@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/nitezs/pcgamedb/db"
-	"github.com/nitezs/pcgamedb/model"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,12 +12,13 @@ import (
 type GetGameInfoByPlatformIDRequest struct {
 	PlatformType string `uri:"platform_type" binding:"required"`
 	PlatformID   int    `uri:"platform_id" binding:"required"`
+	ProjectionRequest
 }
 
 type GetGameInfoByPlatformIDResponse struct {
-	Status   string          `json:"status"`
-	Message  string          `json:"message,omitempty"`
-	GameInfo *model.GameInfo `json:"game_info,omitempty"`
+	Status   string        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	GameInfo *GameInfoView `json:"game_info,omitempty"`
 }
 
 // GetGameInfoByPlatformID retrieves game information by platform and ID.
@@ -29,6 +29,8 @@ type GetGameInfoByPlatformIDResponse struct {
 // @Produce json
 // @Param platform_type path string true "Platform Type"
 // @Param platform_id path int true "Platform ID"
+// @Param view query string false "Named field set (summary, full)"
+// @Param fields query string false "Comma separated fields to return, overrides view"
 // @Success 200 {object} GetGameInfoByPlatformIDResponse
 // @Failure 400 {object} GetGameInfoByPlatformIDResponse
 // @Failure 500 {object} GetGameInfoByPlatformIDResponse
@@ -42,7 +44,22 @@ func GetGameInfoByPlatformIDHandler(c *gin.Context) {
 		})
 		return
 	}
-	gameInfo, err := db.GetGameInfoByPlatformID(req.PlatformType, req.PlatformID)
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfoByPlatformIDResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfoByPlatformIDResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	gameInfo, err := db.GetGameInfoByPlatformID(req.PlatformType, req.PlatformID, fields)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusOK, GetGameInfoByPlatformIDResponse{
@@ -58,7 +75,7 @@ func GetGameInfoByPlatformIDHandler(c *gin.Context) {
 	} else {
 		c.JSON(http.StatusOK, GetGameInfoByPlatformIDResponse{
 			Status:   "ok",
-			GameInfo: gameInfo,
+			GameInfo: newGameInfoView(gameInfo, fields),
 		})
 	}
 }
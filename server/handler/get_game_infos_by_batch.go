@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxBatchKeys = 100
+
+type GetGameInfosByBatchRequest struct {
+	IDs      []string `json:"ids,omitempty"`
+	SteamIDs []int    `json:"steam_ids,omitempty"`
+	IGDBIDs  []int    `json:"igdb_ids,omitempty"`
+}
+
+type GetGameInfosByBatchQuery struct {
+	ProjectionRequest
+}
+
+type GetGameInfosByBatchResponse struct {
+	ListResponse[*GameInfoView]
+	NotFound *GetGameInfosByBatchRequest `json:"not_found,omitempty"`
+}
+
+// GetGameInfosByBatchHandler retrieves many game infos in one request.
+// @Summary Retrieve game infos in batch
+// @Description Retrieves game infos by a mixed list of game info IDs, Steam IDs and IGDB IDs, keys without a match are reported in not_found
+// @Tags game
+// @Accept json
+// @Produce json
+// @Param view query string false "Named field set (summary, full)"
+// @Param fields query string false "Comma separated fields to return, overrides view"
+// @Param body body GetGameInfosByBatchRequest true "Keys to look up (at most 100 in total)"
+// @Success 200 {object} GetGameInfosByBatchResponse
+// @Failure 400 {object} GetGameInfosByBatchResponse
+// @Failure 500 {object} GetGameInfosByBatchResponse
+// @Router /game/batch [post]
+func GetGameInfosByBatchHandler(c *gin.Context) {
+	var req GetGameInfosByBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByBatchResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	var query GetGameInfosByBatchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByBatchResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	fields, err := query.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfosByBatchResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if keys := len(req.IDs) + len(req.SteamIDs) + len(req.IGDBIDs); keys == 0 || keys > maxBatchKeys {
+		c.JSON(http.StatusBadRequest, GetGameInfosByBatchResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: fmt.Sprintf("Between 1 and %d keys are required", maxBatchKeys),
+			},
+		})
+		return
+	}
+	if msg := invalidPlatformIDs(req.SteamIDs, req.IGDBIDs); msg != "" {
+		c.JSON(http.StatusBadRequest, GetGameInfosByBatchResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: msg,
+			},
+		})
+		return
+	}
+	objIDs := make([]primitive.ObjectID, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, GetGameInfosByBatchResponse{
+				ListResponse: ListResponse[*GameInfoView]{
+					Status:  "error",
+					Message: fmt.Sprintf("Invalid ID: %s", id),
+				},
+			})
+			return
+		}
+		objIDs = append(objIDs, objID)
+	}
+	infos, err := db.GetGameInfosByKeys(objIDs, req.SteamIDs, req.IGDBIDs, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetGameInfosByBatchResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	notFound := notFoundKeys(&req, objIDs, infos)
+	c.JSON(http.StatusOK, GetGameInfosByBatchResponse{
+		ListResponse: newListResponse(newGameInfoViews(infos, fields), "", int64(len(infos))),
+		NotFound:     notFound,
+	})
+}
+
+// invalidPlatformIDs returns the error message for the first ID that is not positive,
+// game infos without a Steam or IGDB ID would match it
+func invalidPlatformIDs(steamIDs []int, igdbIDs []int) string {
+	for _, id := range steamIDs {
+		if id <= 0 {
+			return fmt.Sprintf("Invalid Steam ID: %d", id)
+		}
+	}
+	for _, id := range igdbIDs {
+		if id <= 0 {
+			return fmt.Sprintf("Invalid IGDB ID: %d", id)
+		}
+	}
+	return ""
+}
+
+func notFoundKeys(req *GetGameInfosByBatchRequest, objIDs []primitive.ObjectID, infos []*model.GameInfo) *GetGameInfosByBatchRequest {
+	foundIDs := make(map[primitive.ObjectID]bool)
+	foundSteamIDs := make(map[int]bool)
+	foundIGDBIDs := make(map[int]bool)
+	for _, info := range infos {
+		foundIDs[info.ID] = true
+		foundSteamIDs[info.SteamID] = true
+		foundIGDBIDs[info.IGDBID] = true
+	}
+	notFound := &GetGameInfosByBatchRequest{}
+	for i, id := range objIDs {
+		if !foundIDs[id] {
+			notFound.IDs = append(notFound.IDs, req.IDs[i])
+		}
+	}
+	for _, id := range req.SteamIDs {
+		if !foundSteamIDs[id] {
+			notFound.SteamIDs = append(notFound.SteamIDs, id)
+		}
+	}
+	for _, id := range req.IGDBIDs {
+		if !foundIGDBIDs[id] {
+			notFound.IGDBIDs = append(notFound.IGDBIDs, id)
+		}
+	}
+	if len(notFound.IDs) == 0 && len(notFound.SteamIDs) == 0 && len(notFound.IGDBIDs) == 0 {
+		return nil
+	}
+	return notFound
+}
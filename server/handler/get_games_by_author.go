@@ -10,9 +10,8 @@ import (
 )
 
 type GetGameItemsByAuthorRequest struct {
-	Author   string `uri:"author" binding:"required"`
-	Page     int    `form:"page" json:"page"`
-	PageSize int    `form:"page_size" json:"page_size"`
+	Author string `uri:"author" binding:"required"`
+	PaginationRequest
 }
 
 type GetGameItemsByAuthorResponse struct {
@@ -22,6 +21,8 @@ type GetGameItemsByAuthorResponse struct {
 	GameItems []*model.GameItem `json:"game_downloads,omitempty"`
 }
 
+type GetGameItemsByAuthorListResponse = ListResponse[*model.GameItem]
+
 // GetGameItemsByAuthorHandler returns all game downloads by author
 // @Summary Get game downloads by author
 // @Description Get game downloads by author
@@ -51,16 +52,8 @@ func GetGameItemsByAuthorHandler(ctx *gin.Context) {
 		})
 		return
 	}
-	if req.Page == 0 || req.Page < 0 {
-		req.Page = 1
-	}
-	if req.PageSize == 0 || req.PageSize < 0 {
-		req.PageSize = 10
-	}
-	if req.PageSize > 10 {
-		req.PageSize = 10
-	}
-	downloads, totalPage, err := db.GetGameItemsByAuthorPagination(req.Author, req.Page, req.PageSize)
+	page := req.legacyPage(10)
+	downloads, _, total, err := db.GetGameItemsByAuthorPage(req.Author, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, GetGameItemsByAuthorResponse{
 			Status:  "error",
@@ -77,7 +70,43 @@ func GetGameItemsByAuthorHandler(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, GetGameItemsByAuthorResponse{
 		Status:    "ok",
-		TotalPage: totalPage,
+		TotalPage: totalPages(total, req.PageSize),
 		GameItems: downloads,
 	})
 }
+
+// GetGameItemsByAuthorListHandler returns a page of the game downloads of an author in the list envelope
+func GetGameItemsByAuthorListHandler(ctx *gin.Context) {
+	var req GetGameItemsByAuthorRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, GetGameItemsByAuthorListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, GetGameItemsByAuthorListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	page, err := req.page(maxPageLimit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, GetGameItemsByAuthorListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	downloads, next, total, err := db.GetGameItemsByAuthorPage(req.Author, page)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, GetGameItemsByAuthorListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, newListResponse(downloads, next, total))
+}
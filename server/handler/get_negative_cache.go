@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/crawler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NegativeCacheRequest struct {
+	Name string `form:"name" json:"name" binding:"required"`
+}
+
+type GetNegativeCacheResponse struct {
+	Status  string                  `json:"status"`
+	Message string                  `json:"message,omitempty"`
+	Entries []crawler.NegativeEntry `json:"entries,omitempty"`
+}
+
+// GetNegativeCacheHandler returns the cached failed ID lookups of a name
+// @Summary Show failed ID lookups
+// @Description Show why a game name did not resolve to an IGDB or Steam ID, reasons are not_found, ambiguous and api_error
+// @Tags cache
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param name query string true "Game name as crawled"
+// @Success 200 {object} GetNegativeCacheResponse
+// @Failure 400 {object} GetNegativeCacheResponse
+// @Security BearerAuth
+// @Router /cache/negative [get]
+func GetNegativeCacheHandler(c *gin.Context) {
+	var req NegativeCacheRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetNegativeCacheResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, GetNegativeCacheResponse{
+		Status:  "ok",
+		Entries: crawler.GetNegativeEntries(req.Name),
+	})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetNotifiersResponse = ListResponse[*model.Notifier]
+
+// GetNotifiersHandler lists the chat notifiers
+// @Summary List notifiers
+// @Description List the notifiers of the API key owner, or all notifiers for admin keys. Bot tokens, access tokens and Discord webhook URLs are not included
+// @Tags notifier
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Success 200 {object} GetNotifiersResponse
+// @Failure 500 {object} GetNotifiersResponse
+// @Security BearerAuth
+// @Router /notifier [get]
+func GetNotifiersHandler(c *gin.Context) {
+	var notifiers []*model.Notifier
+	var err error
+	if middleware.IsAdmin(c) {
+		notifiers, err = db.GetAllNotifiers()
+	} else {
+		notifiers, err = db.GetNotifiersByOwner(middleware.Owner(c))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetNotifiersResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	for _, notifier := range notifiers {
+		notifier.Redact()
+	}
+	c.JSON(http.StatusOK, newListResponse(notifiers, "", int64(len(notifiers))))
+}
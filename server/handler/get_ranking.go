@@ -2,19 +2,28 @@ package handler
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/nitezs/pcgamedb/crawler"
-	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/db"
 
 	"github.com/gin-gonic/gin"
 )
 
+type GetRankingRequest struct {
+	Type string `uri:"type" binding:"required"`
+	PaginationRequest
+	ProjectionRequest
+}
+
 type GetRankingResponse struct {
-	Status  string            `json:"status"`
-	Message string            `json:"message,omitempty"`
-	Games   []*model.GameInfo `json:"games"`
+	Status  string          `json:"status"`
+	Message string          `json:"message,omitempty"`
+	Games   []*GameInfoView `json:"games"`
 }
 
+type GetRankingListResponse = ListResponse[*GameInfoView]
+
 // GetRanking retrieves game rankings.
 // @Summary Retrieve rankings
 // @Description Retrieves rankings based on a specified type
@@ -22,36 +31,52 @@ type GetRankingResponse struct {
 // @Accept json
 // @Produce json
 // @Param type path string true "Ranking Type(top, week-top, best-of-the-year, most-played)"
+// @Param view query string false "Named field set (summary, full)"
+// @Param fields query string false "Comma separated fields to return, overrides view"
 // @Success 200 {object} GetRankingResponse
 // @Failure 400 {object} GetRankingResponse
 // @Failure 500 {object} GetRankingResponse
 // @Router /ranking/{type} [get]
 func GetRankingHandler(c *gin.Context) {
-	rankingType, exist := c.Params.Get("type")
-	if !exist {
+	var req GetRankingRequest
+	if err := c.ShouldBindUri(&req); err != nil {
 		c.JSON(http.StatusBadRequest, GetRankingResponse{
 			Status:  "error",
-			Message: "Missing ranking type",
+			Message: err.Error(),
 		})
+		return
 	}
-	var f func() ([]*model.GameInfo, error)
-	switch rankingType {
-	case "top":
-		f = crawler.GetSteam250Top250Cache
-	case "week-top":
-		f = crawler.GetSteam250WeekTop50Cache
-	case "best-of-the-year":
-		f = crawler.GetSteam250BestOfTheYearCache
-	case "most-played":
-		f = crawler.GetSteam250MostPlayedCache
-	default:
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetRankingResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetRankingResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if !slices.Contains(crawler.Steam250RankingTypes, req.Type) {
 		c.JSON(http.StatusBadRequest, GetRankingResponse{
 			Status:  "error",
 			Message: "Invalid ranking type",
 		})
 		return
 	}
-	rank, err := f()
+	rank, err := crawler.GetSteam250RankingCache(req.Type)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetRankingResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	games, err := db.GetGameInfosByIDs(rank, fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetRankingResponse{
 			Status:  "error",
@@ -61,6 +86,66 @@ func GetRankingHandler(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, GetRankingResponse{
 		Status: "ok",
-		Games:  rank,
+		Games:  newGameInfoViews(games, fields),
 	})
 }
+
+// GetRankingListHandler returns a page of a ranking in the list envelope
+func GetRankingListHandler(c *gin.Context) {
+	var req GetRankingRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetRankingListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetRankingListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	page, err := req.page(maxPageLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetRankingListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetRankingListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if !slices.Contains(crawler.Steam250RankingTypes, req.Type) {
+		c.JSON(http.StatusBadRequest, GetRankingListResponse{
+			Status:  "error",
+			Message: "Invalid ranking type",
+		})
+		return
+	}
+	rank, err := crawler.GetSteam250RankingCache(req.Type)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetRankingListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	ids, next := db.PaginateSlice(rank, page)
+	games, err := db.GetGameInfosByIDs(ids, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetRankingListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(newGameInfoViews(games, fields), next, int64(len(rank))))
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetReviewsRequest struct {
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=pending accepted rejected"`
+	PaginationRequest
+}
+
+type GetReviewsResponse = ListResponse[*model.MatchReview]
+
+// GetReviewsHandler returns the review queue
+// @Summary List the review queue
+// @Description List the game items that could not be organized automatically, oldest first, with the reason and the scored candidates
+// @Tags review
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param status query string false "pending (default), accepted or rejected"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Number of items per page (max 50)"
+// @Param offset query int false "Number of items to skip when no cursor is given"
+// @Success 200 {object} GetReviewsResponse
+// @Failure 400 {object} GetReviewsResponse
+// @Failure 500 {object} GetReviewsResponse
+// @Security BearerAuth
+// @Router /review [get]
+func GetReviewsHandler(c *gin.Context) {
+	var req GetReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetReviewsResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Status == "" {
+		req.Status = model.MatchReviewPending
+	}
+	page, err := req.page(maxPageLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetReviewsResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	reviews, next, total, err := db.GetMatchReviewsPage(req.Status, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetReviewsResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(reviews, next, total))
+}
@@ -11,6 +11,7 @@ import (
 
 type GetUnorganizedGameItemsRequest struct {
 	Num int `json:"num" form:"num"`
+	PaginationRequest
 }
 
 type GetUnorganizedGameItemsResponse struct {
@@ -20,6 +21,8 @@ type GetUnorganizedGameItemsResponse struct {
 	GameItems []*model.GameItem `json:"game_downloads,omitempty"`
 }
 
+type GetUnorganizedGameItemsListResponse = ListResponse[*model.GameItem]
+
 // GetUnorganizedGameItems retrieves a list of unorganized game downloads.
 // @Summary List unorganized game downloads
 // @Description Retrieves game downloads that have not been organized
@@ -64,3 +67,35 @@ func GetUnorganizedGameItemsHandler(c *gin.Context) {
 		Size:      len(gameDownloads),
 	})
 }
+
+// GetUnorganizedGameItemsListHandler returns a page of the unorganized game downloads in the list envelope
+func GetUnorganizedGameItemsListHandler(c *gin.Context) {
+	var req GetUnorganizedGameItemsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetUnorganizedGameItemsListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Limit <= 0 && req.Num > 0 {
+		req.Limit = req.Num
+	}
+	page, err := req.page(maxPageLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetUnorganizedGameItemsListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	gameDownloads, next, total, err := db.GetUnorganizedGameItemsPage(page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetUnorganizedGameItemsListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(gameDownloads, next, total))
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetWatchlistResponse = ListResponse[*model.WatchlistEntry]
+
+// GetWatchlistHandler lists the watchlist of the API key owner
+// @Summary Get watchlist
+// @Description List the games watched by the owner of the API key
+// @Tags watchlist
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Success 200 {object} GetWatchlistResponse
+// @Failure 500 {object} GetWatchlistResponse
+// @Security BearerAuth
+// @Router /watchlist [get]
+func GetWatchlistHandler(c *gin.Context) {
+	entries, err := db.GetWatchlistByOwner(middleware.Owner(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetWatchlistResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(entries, "", int64(len(entries))))
+}
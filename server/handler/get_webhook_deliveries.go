@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetWebhookDeliveriesRequest struct {
+	ID string `uri:"id" json:"id" binding:"required"`
+	PaginationRequest
+}
+
+type GetWebhookDeliveriesResponse = ListResponse[*model.WebhookDelivery]
+
+// GetWebhookDeliveriesHandler returns the delivery log of a webhook
+// @Summary List webhook deliveries
+// @Description List the deliveries of a webhook, newest first
+// @Tags webhook
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Webhook ID"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Number of items per page (max 50)"
+// @Param offset query int false "Number of items to skip when no cursor is given"
+// @Success 200 {object} GetWebhookDeliveriesResponse
+// @Failure 400 {object} GetWebhookDeliveriesResponse
+// @Failure 500 {object} GetWebhookDeliveriesResponse
+// @Security BearerAuth
+// @Router /webhook/{id}/deliveries [get]
+func GetWebhookDeliveriesHandler(c *gin.Context) {
+	var req GetWebhookDeliveriesRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetWebhookDeliveriesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetWebhookDeliveriesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetWebhookDeliveriesResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	page, err := req.page(maxPageLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetWebhookDeliveriesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	deliveries, next, total, err := db.GetWebhookDeliveriesPage(id, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetWebhookDeliveriesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, newListResponse(deliveries, next, total))
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetWebhooksResponse = ListResponse[*model.Webhook]
+
+// GetWebhooksHandler lists the webhook subscriptions
+// @Summary List webhooks
+// @Description List webhook subscriptions, secrets are not included
+// @Tags webhook
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Success 200 {object} GetWebhooksResponse
+// @Failure 500 {object} GetWebhooksResponse
+// @Security BearerAuth
+// @Router /webhook [get]
+func GetWebhooksHandler(c *gin.Context) {
+	hooks, err := db.GetAllWebhooks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetWebhooksResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	for _, hook := range hooks {
+		hook.Secret = ""
+	}
+	c.JSON(http.StatusOK, newListResponse(hooks, "", int64(len(hooks))))
+}
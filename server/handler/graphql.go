@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/server/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLHandler executes a GraphQL query.
+// @Summary GraphQL
+// @Description Executes a GraphQL query over game infos, game items, authors and rankings, see server/graph/schema.graphql
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param body body GraphQLRequest true "GraphQL Request"
+// @Success 200 {object} object
+// @Failure 400 {object} object
+// @Router /graphql [post]
+func GraphQLHandler(c *gin.Context) {
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"errors": []gin.H{{"message": err.Error()}},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, graph.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables))
+}
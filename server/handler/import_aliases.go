@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportAliasesRequest struct {
+	Aliases   []*model.NameAlias `json:"aliases" binding:"required"`
+	Overwrite bool               `json:"overwrite"`
+}
+
+type ImportAliasesResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Imported int    `json:"imported"`
+}
+
+// ImportAliasesHandler adds aliases exported by another instance
+// @Summary Import name aliases
+// @Description Import aliases exported by GET /alias. Names are normalized again, aliases of names that already have one are kept unless overwrite is set.
+// @Tags alias
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param body body ImportAliasesRequest true "Aliases to import"
+// @Success 200 {object} ImportAliasesResponse
+// @Failure 400 {object} ImportAliasesResponse
+// @Failure 500 {object} ImportAliasesResponse
+// @Security BearerAuth
+// @Router /alias [post]
+func ImportAliasesHandler(c *gin.Context) {
+	var req ImportAliasesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ImportAliasesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	count, err := crawler.ImportAliases(req.Aliases, req.Overwrite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ImportAliasesResponse{
+			Status:   "error",
+			Message:  err.Error(),
+			Imported: count,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, ImportAliasesResponse{
+		Status:   "ok",
+		Message:  "Aliases imported successfully",
+		Imported: count,
+	})
+}
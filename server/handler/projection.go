@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+)
+
+// ProjectionRequest is embedded by the requests of endpoints returning game infos.
+// Fields is a comma separated list of json field names and takes precedence over View (summary, full).
+type ProjectionRequest struct {
+	View   string `form:"view" json:"view"`
+	Fields string `form:"fields" json:"fields"`
+}
+
+func (req *ProjectionRequest) fields() ([]string, error) {
+	return db.ParseGameInfoFields(req.View, req.Fields)
+}
+
+// GameInfoView is a game info loaded with the projection of the request.
+// The fields that were not loaded are left out of the response instead of being sent empty.
+type GameInfoView struct {
+	*model.GameInfo
+	fields []string
+}
+
+func newGameInfoView(info *model.GameInfo, fields []string) *GameInfoView {
+	if info == nil {
+		return nil
+	}
+	return &GameInfoView{GameInfo: info, fields: fields}
+}
+
+func newGameInfoViews(infos []*model.GameInfo, fields []string) []*GameInfoView {
+	res := make([]*GameInfoView, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, newGameInfoView(info, fields))
+	}
+	return res
+}
+
+func (v GameInfoView) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(v.GameInfo)
+	if err != nil || v.fields == nil {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	selected := make(map[string]json.RawMessage, len(v.fields))
+	for _, field := range v.fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	return json.Marshal(selected)
+}
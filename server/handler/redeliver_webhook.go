@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/webhook"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RedeliverWebhookRequest struct {
+	ID         string `uri:"id" json:"id" binding:"required"`
+	DeliveryID string `uri:"delivery_id" json:"delivery_id" binding:"required"`
+}
+
+type RedeliverWebhookResponse struct {
+	Status   string                 `json:"status"`
+	Message  string                 `json:"message,omitempty"`
+	Delivery *model.WebhookDelivery `json:"delivery,omitempty"`
+}
+
+// RedeliverWebhookHandler sends a logged delivery again
+// @Summary Redeliver a webhook delivery
+// @Description Sends the payload of a delivery again as a new delivery and returns its result
+// @Tags webhook
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} RedeliverWebhookResponse
+// @Failure 400 {object} RedeliverWebhookResponse
+// @Failure 404 {object} RedeliverWebhookResponse
+// @Failure 500 {object} RedeliverWebhookResponse
+// @Security BearerAuth
+// @Router /webhook/{id}/deliveries/{delivery_id}/redeliver [post]
+func RedeliverWebhookHandler(c *gin.Context) {
+	var req RedeliverWebhookRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, RedeliverWebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	hookID, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, RedeliverWebhookResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	deliveryID, err := primitive.ObjectIDFromHex(req.DeliveryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, RedeliverWebhookResponse{
+			Status:  "error",
+			Message: "Invalid delivery ID",
+		})
+		return
+	}
+	delivery, err := webhook.Redeliver(hookID, deliveryID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, RedeliverWebhookResponse{
+				Status:  "error",
+				Message: "Delivery not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, RedeliverWebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, RedeliverWebhookResponse{
+		Status:   "ok",
+		Delivery: delivery,
+	})
+}
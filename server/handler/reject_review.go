@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RejectReviewHandler rejects all candidates of a queued game item
+// @Summary Reject a review
+// @Description Record that none of the candidates of a pending review is right, the game item stays unorganized and is not matched automatically again
+// @Tags review
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Review ID"
+// @Success 200 {object} ReviewResponse
+// @Failure 400 {object} ReviewResponse
+// @Failure 404 {object} ReviewResponse
+// @Failure 409 {object} ReviewResponse
+// @Failure 500 {object} ReviewResponse
+// @Security BearerAuth
+// @Router /review/{id}/reject [post]
+func RejectReviewHandler(c *gin.Context) {
+	review, ok := bindReview(c)
+	if !ok {
+		return
+	}
+	if err := crawler.RejectMatchReview(review, middleware.Owner(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, ReviewResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, ReviewResponse{
+		Status:  "ok",
+		Message: "Review rejected successfully",
+		Review:  review,
+	})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/nitezs/pcgamedb/db"
+)
+
+// ListResponse is the envelope shared by the list endpoints of the /v2 routes.
+// The unversioned routes keep the list responses they had before it.
+type ListResponse[T any] struct {
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+// PaginationRequest is embedded by the requests of paginated list endpoints.
+// Cursor takes precedence over Offset, Page and PageSize are kept for older clients.
+type PaginationRequest struct {
+	Cursor   string `form:"cursor" json:"cursor"`
+	Limit    int    `form:"limit" json:"limit"`
+	Offset   int    `form:"offset" json:"offset"`
+	Page     int    `form:"page" json:"page"`
+	PageSize int    `form:"page_size" json:"page_size"`
+}
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 50
+)
+
+func (req *PaginationRequest) page(maxLimit int) (*db.Page, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = req.PageSize
+	}
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	offset := req.Offset
+	if offset <= 0 && req.Page > 1 {
+		offset = (req.Page - 1) * limit
+	}
+	return db.NewPage(req.Cursor, offset, limit)
+}
+
+// legacyPage is the page of the page and page_size parameters of the unversioned list endpoints
+func (req *PaginationRequest) legacyPage(maxPageSize int) *db.Page {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+	return &db.Page{Offset: (req.Page - 1) * req.PageSize, Limit: req.PageSize}
+}
+
+// totalPages is the total_page of the unversioned list endpoints
+func totalPages(total int64, pageSize int) int {
+	return int((total + int64(pageSize) - 1) / int64(pageSize))
+}
+
+func newListResponse[T any](items []T, next string, total int64) ListResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	res := ListResponse[T]{
+		Status:     "ok",
+		Data:       items,
+		NextCursor: next,
+		Total:      total,
+	}
+	if len(items) == 0 {
+		res.Message = "No results found"
+	}
+	return res
+}
@@ -1,34 +1,66 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"time"
+	"unicode/utf8"
 
 	"github.com/nitezs/pcgamedb/db"
 	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type SearchGamesRequest struct {
-	Keyword  string `form:"keyword" json:"keyword" binding:"required,min=4,max=64"`
-	Page     int    `form:"page" json:"page"`
-	PageSize int    `form:"page_size" json:"page_size"`
+	Keyword      string `form:"keyword" json:"keyword" binding:"max=64"`
+	Author       string `form:"author" json:"author"`
+	Developer    string `form:"developer" json:"developer"`
+	Publisher    string `form:"publisher" json:"publisher"`
+	Language     string `form:"language" json:"language"`
+	HasSteamID   *bool  `form:"has_steam_id" json:"has_steam_id"`
+	MinSize      string `form:"min_size" json:"min_size"`
+	MaxSize      string `form:"max_size" json:"max_size"`
+	UpdatedSince string `form:"updated_since" json:"updated_since"`
+	// Facets adds the facet counts of the filtered result set
+	Facets bool `form:"facets" json:"facets"`
+	PaginationRequest
+	ProjectionRequest
 }
 
 type SearchGamesResponse struct {
-	Status    string            `json:"status"`
-	Message   string            `json:"message,omitempty"`
-	TotalPage int               `json:"total_page,omitempty"`
-	GameInfos []*model.GameInfo `json:"game_infos,omitempty"`
+	Status    string              `json:"status"`
+	Message   string              `json:"message,omitempty"`
+	TotalPage int                 `json:"total_page,omitempty"`
+	GameInfos []*GameInfoView     `json:"game_infos,omitempty"`
+	Facets    *model.SearchFacets `json:"facets,omitempty"`
 }
 
-// SearchGames searches for games based on a keyword.
+type SearchGamesListResponse struct {
+	ListResponse[*GameInfoView]
+	Facets *model.SearchFacets `json:"facets,omitempty"`
+}
+
+// SearchGames searches for games based on a keyword and filters.
 // @Summary Search games
-// @Description Searches for games based on the provided keyword
+// @Description Searches for games based on the provided keyword and filters, facet counts of the filtered result set are returned alongside with facets=true.
+// @Description The keyword must be at least 4 characters unless a filter is given.
 // @Tags game
 // @Accept json
 // @Produce json
-// @Param keyword query string true "Search keyword"
+// @Param keyword query string false "Search keyword"
+// @Param author query string false "Download author"
+// @Param developer query string false "Developer"
+// @Param publisher query string false "Publisher"
+// @Param language query string false "Language"
+// @Param has_steam_id query bool false "Whether the game has a Steam ID"
+// @Param min_size query string false "Minimum download size (e.g. 500MB, 20GB)"
+// @Param max_size query string false "Maximum download size (e.g. 500MB, 20GB)"
+// @Param updated_since query string false "Updated since (RFC3339 or 2006-01-02)"
+// @Param facets query bool false "Return the facet counts"
+// @Param view query string false "Named field set (summary, full)"
+// @Param fields query string false "Comma separated fields to return, overrides view"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Number of items per page"
 // @Success 200 {object} SearchGamesResponse
@@ -44,16 +76,31 @@ func SearchGamesHandler(c *gin.Context) {
 		})
 		return
 	}
-	if req.Page == 0 || req.Page < 0 {
-		req.Page = 1
+	filter, err := req.filter()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, SearchGamesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
 	}
-	if req.PageSize == 0 || req.PageSize < 0 {
-		req.PageSize = 10
+	if filter.IsEmpty() && utf8.RuneCountInString(req.Keyword) < 4 {
+		c.JSON(http.StatusBadRequest, SearchGamesResponse{
+			Status:  "error",
+			Message: "Keyword must be at least 4 characters when no filter is given",
+		})
+		return
 	}
-	if req.PageSize > 10 {
-		req.PageSize = 10
+	page := req.legacyPage(10)
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, SearchGamesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
 	}
-	items, totalPage, err := db.SearchGameInfosCache(req.Keyword, req.Page, req.PageSize)
+	items, _, total, facets, err := db.SearchGameInfosCache(req.Keyword, filter, page, fields, req.Facets)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, SearchGamesResponse{
 			Status:  "error",
@@ -65,12 +112,112 @@ func SearchGamesHandler(c *gin.Context) {
 		c.JSON(http.StatusOK, SearchGamesResponse{
 			Status:  "ok",
 			Message: "No results found",
+			Facets:  facets,
 		})
 		return
 	}
 	c.JSON(http.StatusOK, SearchGamesResponse{
 		Status:    "ok",
-		TotalPage: totalPage,
-		GameInfos: items,
+		TotalPage: totalPages(total, req.PageSize),
+		GameInfos: newGameInfoViews(items, fields),
+		Facets:    facets,
 	})
 }
+
+// SearchGamesListHandler returns a page of the search results in the list envelope
+func SearchGamesListHandler(c *gin.Context) {
+	var req SearchGamesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, SearchGamesListResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	filter, err := req.filter()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, SearchGamesListResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if filter.IsEmpty() && utf8.RuneCountInString(req.Keyword) < 4 {
+		c.JSON(http.StatusBadRequest, SearchGamesListResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: "Keyword must be at least 4 characters when no filter is given",
+			},
+		})
+		return
+	}
+	page, err := req.page(10)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, SearchGamesListResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	fields, err := req.fields()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, SearchGamesListResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	items, next, total, facets, err := db.SearchGameInfosCache(req.Keyword, filter, page, fields, req.Facets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, SearchGamesListResponse{
+			ListResponse: ListResponse[*GameInfoView]{
+				Status:  "error",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, SearchGamesListResponse{
+		ListResponse: newListResponse(newGameInfoViews(items, fields), next, total),
+		Facets:       facets,
+	})
+}
+
+func (req *SearchGamesRequest) filter() (*model.SearchFilter, error) {
+	filter := &model.SearchFilter{
+		Author:     req.Author,
+		Developer:  req.Developer,
+		Publisher:  req.Publisher,
+		Language:   req.Language,
+		HasSteamID: req.HasSteamID,
+	}
+	var err error
+	if req.MinSize != "" {
+		if filter.MinSize, err = utils.ParseSize(req.MinSize); err != nil {
+			return nil, err
+		}
+	}
+	if req.MaxSize != "" {
+		if filter.MaxSize, err = utils.ParseSize(req.MaxSize); err != nil {
+			return nil, err
+		}
+	}
+	if req.UpdatedSince != "" {
+		filter.UpdatedSince, err = time.Parse(time.RFC3339, req.UpdatedSince)
+		if err != nil {
+			filter.UpdatedSince, err = time.Parse(time.DateOnly, req.UpdatedSince)
+			if err != nil {
+				return nil, fmt.Errorf("invalid updated_since: %s", req.UpdatedSince)
+			}
+		}
+	}
+	return filter, nil
+}
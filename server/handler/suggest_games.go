@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SuggestGamesRequest struct {
+	Query string `form:"q" json:"q" binding:"required,max=64"`
+	Limit int    `form:"limit" json:"limit"`
+}
+
+type GameSuggestion struct {
+	ID      primitive.ObjectID `json:"id"`
+	Name    string             `json:"name"`
+	Matched string             `json:"matched,omitempty"`
+	Score   float64            `json:"score"`
+}
+
+type SuggestGamesResponse struct {
+	Status      string            `json:"status"`
+	Message     string            `json:"message,omitempty"`
+	Suggestions []*GameSuggestion `json:"suggestions,omitempty"`
+}
+
+type SuggestGamesListResponse = ListResponse[*GameSuggestion]
+
+// SuggestGamesHandler returns autocomplete candidates for a partial or misspelled name.
+// @Summary Suggest games
+// @Description Returns the best typo tolerant matches of game names and aliases from the in-memory name index
+// @Tags game
+// @Accept json
+// @Produce json
+// @Param q query string true "Partial game name"
+// @Param limit query int false "Number of suggestions (max 20)"
+// @Success 200 {object} SuggestGamesResponse
+// @Failure 400 {object} SuggestGamesResponse
+// @Failure 500 {object} SuggestGamesResponse
+// @Router /game/suggest [get]
+func SuggestGamesHandler(c *gin.Context) {
+	var req SuggestGamesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, SuggestGamesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	matches, err := db.FuzzySearchGameInfoNames(req.Query, req.limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, SuggestGamesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if len(matches) == 0 {
+		c.JSON(http.StatusOK, SuggestGamesResponse{
+			Status:  "ok",
+			Message: "No results found",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, SuggestGamesResponse{
+		Status:      "ok",
+		Suggestions: newGameSuggestions(matches),
+	})
+}
+
+// SuggestGamesListHandler returns the autocomplete candidates in the list envelope
+func SuggestGamesListHandler(c *gin.Context) {
+	var req SuggestGamesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, SuggestGamesListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	matches, err := db.FuzzySearchGameInfoNames(req.Query, req.limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, SuggestGamesListResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	suggestions := newGameSuggestions(matches)
+	c.JSON(http.StatusOK, newListResponse(suggestions, "", int64(len(suggestions))))
+}
+
+func (req *SuggestGamesRequest) limit() int {
+	if req.Limit <= 0 {
+		return 10
+	}
+	return min(req.Limit, 20)
+}
+
+func newGameSuggestions(matches []utils.TrigramMatch[primitive.ObjectID]) []*GameSuggestion {
+	suggestions := make([]*GameSuggestion, 0, len(matches))
+	for _, match := range matches {
+		suggestion := &GameSuggestion{
+			ID:    match.Key,
+			Name:  match.Name,
+			Score: match.Score,
+		}
+		if match.Matched != match.Name {
+			suggestion.Matched = match.Matched
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
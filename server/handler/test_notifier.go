@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/notify"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// TestNotifierHandler sends the latest game item to a notifier
+// @Summary Test a notifier
+// @Description Sends the most recently crawled game item to the notifier, ignoring its filters
+// @Tags notifier
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Notifier ID"
+// @Success 200 {object} NotifierResponse
+// @Failure 400 {object} NotifierResponse
+// @Failure 404 {object} NotifierResponse
+// @Failure 502 {object} NotifierResponse
+// @Security BearerAuth
+// @Router /notifier/{id}/test [post]
+func TestNotifierHandler(c *gin.Context) {
+	var req NotifierIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	notifier, err := db.GetNotifierByID(id)
+	if err == nil && !middleware.CanManage(c, notifier.Owner) {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, NotifierResponse{
+				Status:  "error",
+				Message: "Notifier not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	items, err := db.GetLatestGameItems("", 1)
+	if err != nil || len(items) == 0 {
+		c.JSON(http.StatusInternalServerError, NotifierResponse{
+			Status:  "error",
+			Message: "No game item to send",
+		})
+		return
+	}
+	item := items[0]
+	var info *model.GameInfo
+	if infos, err := db.GetGameInfosByGameItemIDs([]primitive.ObjectID{item.ID}); err == nil {
+		info = infos[item.ID]
+	}
+	if err := notify.Send(c.Request.Context(), notifier, notify.NewMessage(item, info, false)); err != nil {
+		// the error may contain what the notifier URL answered, it is only logged
+		log.Logger.Warn("Failed to send test notification", zap.String("notifier_id", notifier.ID.Hex()), zap.Error(err))
+		c.JSON(http.StatusBadGateway, NotifierResponse{
+			Status:  "error",
+			Message: "Failed to send the notification",
+		})
+		return
+	}
+	notifier.Redact()
+	c.JSON(http.StatusOK, NotifierResponse{
+		Status:   "ok",
+		Message:  "Notification sent",
+		Notifier: notifier,
+	})
+}
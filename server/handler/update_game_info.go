@@ -36,7 +36,7 @@ type UpdateGameInfoResponse struct {
 // @Failure 400 {object} handler.UpdateGameInfoResponse
 // @Failure 401 {object} handler.UpdateGameInfoResponse
 // @Failure 500 {object} handler.UpdateGameInfoResponse
-// @Router /game/update [post]
+// @Router /game/update [put]
 func UpdateGameInfoHandler(c *gin.Context) {
 	var req UpdateGameInfoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -67,7 +67,7 @@ func UpdateGameInfoHandler(c *gin.Context) {
 		})
 		return
 	}
-	info, err := db.GetGameInfoByID(objID)
+	info, err := db.GetGameInfoByID(objID, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, UpdateGameInfoResponse{
 			Status:  "error",
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/notify"
+	"github.com/nitezs/pcgamedb/server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type NotifierIDRequest struct {
+	ID string `uri:"id" json:"id" binding:"required"`
+}
+
+// UpdateNotifierRequest changes the given fields, omitted fields are kept.
+// Settings replace the stored settings of the same platform.
+type UpdateNotifierRequest struct {
+	Name          *string                 `json:"name"`
+	Telegram      *model.TelegramSettings `json:"telegram"`
+	Discord       *model.DiscordSettings  `json:"discord"`
+	Matrix        *model.MatrixSettings   `json:"matrix"`
+	Authors       *[]string               `json:"authors"`
+	GameInfoIDs   *[]string               `json:"game_info_ids"`
+	WatchlistOnly *bool                   `json:"watchlist_only"`
+	Active        *bool                   `json:"active"`
+}
+
+// UpdateNotifierHandler updates a chat notifier
+// @Summary Update a notifier
+// @Description Update the settings, filters or active flag of a notifier
+// @Tags notifier
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Notifier ID"
+// @Param body body UpdateNotifierRequest true "Fields to update"
+// @Success 200 {object} NotifierResponse
+// @Failure 400 {object} NotifierResponse
+// @Failure 404 {object} NotifierResponse
+// @Failure 500 {object} NotifierResponse
+// @Security BearerAuth
+// @Router /notifier/{id} [put]
+func UpdateNotifierHandler(c *gin.Context) {
+	var uri NotifierIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(uri.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	var req UpdateNotifierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notifier, err := db.GetNotifierByID(id)
+	if err == nil && !middleware.CanManage(c, notifier.Owner) {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, NotifierResponse{
+				Status:  "error",
+				Message: "Notifier not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Name != nil {
+		notifier.Name = *req.Name
+	}
+	if req.Telegram != nil {
+		notifier.Telegram = req.Telegram
+	}
+	if req.Discord != nil {
+		notifier.Discord = req.Discord
+	}
+	if req.Matrix != nil {
+		notifier.Matrix = req.Matrix
+	}
+	if req.Authors != nil {
+		notifier.Authors = nonNil(*req.Authors)
+	}
+	if req.WatchlistOnly != nil {
+		notifier.WatchlistOnly = *req.WatchlistOnly
+	}
+	if req.GameInfoIDs != nil {
+		notifier.GameInfoIDs, err = parseObjectIDs(*req.GameInfoIDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NotifierResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	if req.Active != nil {
+		notifier.Active = *req.Active
+	}
+	if err := notify.Validate(c.Request.Context(), notifier); err != nil {
+		c.JSON(http.StatusBadRequest, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := db.SaveNotifier(notifier); err != nil {
+		c.JSON(http.StatusInternalServerError, NotifierResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	notify.Invalidate()
+	notifier.Redact()
+	c.JSON(http.StatusOK, NotifierResponse{
+		Status:   "ok",
+		Notifier: notifier,
+	})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/webhook"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WebhookIDRequest struct {
+	ID string `uri:"id" json:"id" binding:"required"`
+}
+
+// UpdateWebhookRequest changes the given fields, omitted fields are kept
+type UpdateWebhookRequest struct {
+	URL     *string   `json:"url"`
+	Events  *[]string `json:"events"`
+	Authors *[]string `json:"authors"`
+	Active  *bool     `json:"active"`
+}
+
+// UpdateWebhookHandler updates a webhook subscription
+// @Summary Update a webhook
+// @Description Update the URL, events, authors or active flag of a webhook
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer <api_key>"
+// @Param id path string true "Webhook ID"
+// @Param body body UpdateWebhookRequest true "Fields to update"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {object} WebhookResponse
+// @Failure 404 {object} WebhookResponse
+// @Failure 500 {object} WebhookResponse
+// @Security BearerAuth
+// @Router /webhook/{id} [put]
+func UpdateWebhookHandler(c *gin.Context) {
+	var uri WebhookIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(uri.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: "Invalid ID",
+		})
+		return
+	}
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	hook, err := db.GetWebhookByID(id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, WebhookResponse{
+				Status:  "error",
+				Message: "Webhook not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.URL != nil {
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		hook.Events = nonNil(*req.Events)
+	}
+	if req.Authors != nil {
+		hook.Authors = nonNil(*req.Authors)
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	if err := validateWebhook(hook.URL, hook.Events); err != nil {
+		c.JSON(http.StatusBadRequest, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := db.SaveWebhook(hook); err != nil {
+		c.JSON(http.StatusInternalServerError, WebhookResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	webhook.Invalidate()
+	hook.Secret = ""
+	c.JSON(http.StatusOK, WebhookResponse{
+		Status:  "ok",
+		Webhook: hook,
+	})
+}
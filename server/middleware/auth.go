@@ -1,25 +1,70 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
 )
 
-func Auth() gin.HandlerFunc {
-	apiKey := config.Config.Server.SecretKey
-	if apiKey == "" {
-		return func(c *gin.Context) {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": "API key is not configured properly.",
-			})
-			c.Abort()
-		}
+const (
+	// ownerKey is the context key of the owner of the API key
+	ownerKey = "owner"
+	// apiKeyKey is the context key of the stored API key, unset for the server secret key
+	apiKeyKey = "api_key"
+)
+
+// AdminOwner owns the resources created with the server secret key
+const AdminOwner = model.AdminOwner
+
+// touchInterval limits the last used updates of a key
+const touchInterval = time.Minute
+
+// Owner returns the owner of the API key of an authenticated request
+func Owner(c *gin.Context) string {
+	return c.GetString(ownerKey)
+}
+
+// APIKey returns the stored API key of an authenticated request, nil for the server secret key
+func APIKey(c *gin.Context) *model.APIKey {
+	if v, ok := c.Get(apiKeyKey); ok {
+		return v.(*model.APIKey)
+	}
+	return nil
+}
+
+// IsAdmin reports whether the request is authenticated with the server secret key or an admin key
+func IsAdmin(c *gin.Context) bool {
+	if _, ok := c.Get(ownerKey); !ok {
+		return false
 	}
+	apiKey := APIKey(c)
+	return apiKey == nil || apiKey.HasScopes(model.ScopeAdmin)
+}
+
+// CanManage reports whether the request may change a resource of owner.
+// Resources of AdminOwner belong to the server secret key, whatever the owner of a stored key is.
+func CanManage(c *gin.Context, owner string) bool {
+	if owner == AdminOwner {
+		return IsAdmin(c)
+	}
+	return Owner(c) == owner || IsAdmin(c)
+}
+
+// Auth accepts the server secret key, which has every scope, and the stored API keys
+// that grant all the given scopes
+func Auth(scopes ...string) gin.HandlerFunc {
+	secretKey := config.Config.Server.SecretKey
 	return func(c *gin.Context) {
 		auth := c.GetHeader("Authorization")
 		if auth == "" {
@@ -30,14 +75,61 @@ func Auth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		if strings.TrimPrefix(auth, "Bearer ") != apiKey {
+		key := strings.TrimPrefix(auth, "Bearer ")
+		if secretKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(secretKey)) == 1 {
+			c.Set(ownerKey, AdminOwner)
+			c.Next()
+			return
+		}
+		apiKey, err := db.GetAPIKeyByKey(key)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"status":  "error",
+					"message": "Unauthorized. Invalid API key.",
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"status":  "error",
+					"message": "Failed to check API key.",
+				})
+			}
+			c.Abort()
+			return
+		}
+		if apiKey.Revoked() || apiKey.Expired() {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"status":  "error",
-				"message": "Unauthorized. Invalid API key.",
+				"message": "Unauthorized. API key is revoked or expired.",
 			})
 			c.Abort()
 			return
 		}
+		if !apiKey.HasScopes(scopes...) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Forbidden. API key is missing scope " + strings.Join(scopes, ", ") + ".",
+			})
+			c.Abort()
+			return
+		}
+		if !checkQuota(c, apiKey) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "Daily quota of the API key exceeded.",
+			})
+			c.Abort()
+			return
+		}
+		if now := time.Now(); apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > touchInterval {
+			go func() {
+				if err := db.TouchAPIKey(apiKey.ID, now); err != nil {
+					log.Logger.Warn("Failed to update API key last used time", zap.Error(err))
+				}
+			}()
+		}
+		c.Set(ownerKey, apiKey.Owner)
+		c.Set(apiKeyKey, apiKey)
 		c.Next()
 	}
 }
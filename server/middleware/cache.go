@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nitezs/pcgamedb/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+type cachedResponse struct {
+	ContentType  string    `json:"content_type"`
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Cache caches the 200 responses of GET requests by path and query for ttl.
+// Entries are stored in the configured cache backend, tagged with tags and the IDs
+// of the documents in the response, and are dropped when one of them is invalidated, see cache.InvalidateTags.
+// Responses carry an ETag and, if the documents have an updated_at, a Last-Modified,
+// and conditional requests are answered with 304.
+func Cache(ttl time.Duration, tags ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		key := responseCacheKey(c.Request.URL)
+		if entry, ok := cache.Load[*cachedResponse](key); ok {
+			c.Header("X-Cache", "HIT")
+			writeCachedResponse(c, entry)
+			c.Abort()
+			return
+		}
+		// taken before the handler reads, so that a concurrent write invalidates the entry
+		createdAt := time.Now()
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+		if w.status != http.StatusOK {
+			c.Writer.WriteHeader(w.status)
+			_, _ = c.Writer.Write(w.body.Bytes())
+			return
+		}
+		body := w.body.Bytes()
+		sum := sha1.Sum(body)
+		entry := &cachedResponse{
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        body,
+			ETag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+		}
+		var entryTags []string
+		entryTags, entry.LastModified = documentTags(body, tags)
+		cache.Store(key, entry, ttl, createdAt, entryTags...)
+		c.Header("X-Cache", "MISS")
+		writeCachedResponse(c, entry)
+	}
+}
+
+// bufferedWriter holds the response back until it is cached
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+func responseCacheKey(u *url.URL) string {
+	// Encode sorts the query by key
+	return cache.NamespaceHTTPResponse.Key(u.Path + "?" + u.Query().Encode())
+}
+
+// documentTags adds the tags of the documents in a JSON body to tags,
+// and returns the latest updated_at of the documents
+func documentTags(body []byte, tags []string) ([]string, time.Time) {
+	res := append([]string{}, tags...)
+	var lastModified time.Time
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return res, lastModified
+	}
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if id, ok := v["id"].(string); ok && len(id) == 24 {
+				res = append(res, cache.DocTag(id))
+			}
+			if s, ok := v["updated_at"].(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil && t.After(lastModified) {
+					lastModified = t
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	return res, lastModified
+}
+
+func writeCachedResponse(c *gin.Context, entry *cachedResponse) {
+	c.Header("ETag", entry.ETag)
+	c.Header("Cache-Control", "public, no-cache")
+	if !entry.LastModified.IsZero() {
+		c.Header("Last-Modified", entry.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if responseNotModified(c.Request, entry) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, entry.ContentType, entry.Body)
+}
+
+func responseNotModified(r *http.Request, entry *cachedResponse) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == entry.ETag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" && !entry.LastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !entry.LastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
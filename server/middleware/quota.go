@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/cache"
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// quotaCounts counts the requests of the current day when Redis is not available
+var (
+	quotaMutx   = &sync.Mutex{}
+	quotaDay    string
+	quotaCounts = map[string]int64{}
+)
+
+// checkQuota counts the request against the daily quota of the key and sets the X-Quota-* headers.
+// Only routes behind Auth count, public read routes are limited by RateLimit instead.
+func checkQuota(c *gin.Context, apiKey *model.APIKey) bool {
+	if apiKey.DailyQuota <= 0 {
+		return true
+	}
+	now := time.Now().UTC()
+	day := now.Format("20060102")
+	count, err := countQuota(apiKey.ID.Hex(), day)
+	if err != nil {
+		// an unavailable counter should not lock every key out
+		log.Logger.Warn("Failed to count API key quota", zap.Error(err))
+		return true
+	}
+	remaining := int64(apiKey.DailyQuota) - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	reset := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	c.Header("X-Quota-Limit", strconv.Itoa(apiKey.DailyQuota))
+	c.Header("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+	c.Header("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return count <= int64(apiKey.DailyQuota)
+}
+
+func countQuota(id string, day string) (int64, error) {
+	if config.Config.RedisAvaliable {
+		return cache.Incr(cache.NamespaceQuota.Key(id, day), 25*time.Hour)
+	}
+	quotaMutx.Lock()
+	defer quotaMutx.Unlock()
+	if quotaDay != day {
+		quotaDay = day
+		quotaCounts = map[string]int64{}
+	}
+	quotaCounts[id]++
+	return quotaCounts[id], nil
+}
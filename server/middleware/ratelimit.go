@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/cache"
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Rate limit buckets, search endpoints are the expensive ones
+const (
+	RateLimitSearch = "search"
+	RateLimitDetail = "detail"
+)
+
+const apiKeyCacheTTL = 30 * time.Second
+
+type rateWindow struct {
+	count int64
+	reset time.Time
+}
+
+var (
+	rateMutx    = &sync.Mutex{}
+	rateWindows = map[string]*rateWindow{}
+	rateSweepAt time.Time
+
+	apiKeyCacheMutx = &sync.Mutex{}
+	apiKeyCache     = map[string]cachedAPIKey{}
+
+	connMutx    = &sync.Mutex{}
+	connections = map[string]int{}
+)
+
+type cachedAPIKey struct {
+	key      *model.APIKey
+	cachedAt time.Time
+}
+
+// RateLimit limits the requests per window of each client in bucket, clients are
+// identified by a valid API key or else by IP. Requests with the server secret key are not limited.
+func RateLimit(bucket string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := bucketLimit(bucket)
+		if limit <= 0 || config.Config.RateLimit.Window <= 0 {
+			c.Next()
+			return
+		}
+		client, keyed, exempt := rateClient(c)
+		if exempt {
+			c.Next()
+			return
+		}
+		if keyed && config.Config.RateLimit.KeyMultiplier > 1 {
+			limit *= config.Config.RateLimit.KeyMultiplier
+		}
+		window := time.Duration(config.Config.RateLimit.Window) * time.Second
+		count, reset, err := countRate(bucket+":"+client, window)
+		if err != nil {
+			log.Logger.Warn("Failed to count rate limit", zap.Error(err))
+			c.Next()
+			return
+		}
+		remaining := int64(limit) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		resetIn := int(time.Until(reset).Seconds() + 0.5)
+		c.Header("RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("RateLimit-Reset", strconv.Itoa(resetIn))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit, config.Config.RateLimit.Window))
+		if count > int64(limit) {
+			c.Header("Retry-After", strconv.Itoa(resetIn))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "Too many requests. Retry in " + strconv.Itoa(resetIn) + " seconds.",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// LimitConnections limits the requests of each client that are served at once,
+// for long lived connections such as event streams. Clients are identified as by RateLimit.
+func LimitConnections() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := config.Config.RateLimit.Connections
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		client, keyed, exempt := rateClient(c)
+		if exempt {
+			c.Next()
+			return
+		}
+		if keyed && config.Config.RateLimit.KeyMultiplier > 1 {
+			limit *= config.Config.RateLimit.KeyMultiplier
+		}
+		connMutx.Lock()
+		if connections[client] >= limit {
+			connMutx.Unlock()
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "Too many open connections. Close one before opening another.",
+			})
+			c.Abort()
+			return
+		}
+		connections[client]++
+		connMutx.Unlock()
+		defer func() {
+			connMutx.Lock()
+			if connections[client]--; connections[client] <= 0 {
+				delete(connections, client)
+			}
+			connMutx.Unlock()
+		}()
+		c.Next()
+	}
+}
+
+// rateClient identifies the client of a request by its valid API key, or else by IP,
+// requests with unknown keys count against their IP. exempt is set for the server secret key.
+func rateClient(c *gin.Context) (client string, keyed bool, exempt bool) {
+	auth := c.GetHeader("Authorization")
+	if auth != "" {
+		key := strings.TrimPrefix(auth, "Bearer ")
+		secretKey := config.Config.Server.SecretKey
+		if secretKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(secretKey)) == 1 {
+			return "", false, true
+		}
+		if apiKey := lookupAPIKey(key); apiKey != nil {
+			return "key:" + apiKey.ID.Hex(), true, false
+		}
+	}
+	return "ip:" + c.ClientIP(), false, false
+}
+
+func bucketLimit(bucket string) int {
+	switch bucket {
+	case RateLimitSearch:
+		return config.Config.RateLimit.Search
+	case RateLimitDetail:
+		return config.Config.RateLimit.Detail
+	default:
+		return 0
+	}
+}
+
+// countRate counts a request in the current fixed window of key
+func countRate(key string, window time.Duration) (int64, time.Time, error) {
+	now := time.Now()
+	start := now.Truncate(window)
+	reset := start.Add(window)
+	if config.Config.RedisAvaliable {
+		count, err := cache.Incr(cache.NamespaceRateLimit.Key(key, start.Unix()), window)
+		return count, reset, err
+	}
+	rateMutx.Lock()
+	defer rateMutx.Unlock()
+	if now.After(rateSweepAt) {
+		for k, w := range rateWindows {
+			if !now.Before(w.reset) {
+				delete(rateWindows, k)
+			}
+		}
+		rateSweepAt = now.Add(window)
+	}
+	w, ok := rateWindows[key]
+	if !ok || !now.Before(w.reset) {
+		w = &rateWindow{reset: reset}
+		rateWindows[key] = w
+	}
+	w.count++
+	return w.count, w.reset, nil
+}
+
+// lookupAPIKey returns the usable stored key, cached briefly so that
+// rate limiting does not query the database on every request.
+// Strings that are not keys are not looked up, and unknown keys are cached too.
+func lookupAPIKey(key string) *model.APIKey {
+	if !db.IsAPIKey(key) {
+		return nil
+	}
+	apiKeyCacheMutx.Lock()
+	cached, ok := apiKeyCache[key]
+	apiKeyCacheMutx.Unlock()
+	if !ok || time.Since(cached.cachedAt) > apiKeyCacheTTL {
+		apiKey, err := db.GetAPIKeyByKey(key)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				log.Logger.Warn("Failed to look up API key", zap.Error(err))
+				return nil
+			}
+			apiKey = nil
+		}
+		cached = cachedAPIKey{key: apiKey, cachedAt: time.Now()}
+		apiKeyCacheMutx.Lock()
+		if len(apiKeyCache) > 10000 {
+			apiKeyCache = map[string]cachedAPIKey{}
+		}
+		apiKeyCache[key] = cached
+		apiKeyCacheMutx.Unlock()
+	}
+	if cached.key == nil || cached.key.Revoked() || cached.key.Expired() {
+		return nil
+	}
+	return cached.key
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nitezs/pcgamedb/config"
+)
+
+func TestCountRate(t *testing.T) {
+	if config.Config.RedisAvaliable {
+		t.Skip("counts are kept in Redis")
+	}
+	const window = time.Hour
+	tests := []struct {
+		name string
+		keys []string
+		want []int64
+	}{
+		{name: "counts per key", keys: []string{"a", "a", "a"}, want: []int64{1, 2, 3}},
+		{name: "keys are separate", keys: []string{"a", "b", "a", "b", "c"}, want: []int64{1, 1, 2, 2, 1}},
+		{name: "buckets are separate", keys: []string{"search:ip:1", "detail:ip:1", "search:ip:1"}, want: []int64{1, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rateMutx.Lock()
+			rateWindows = map[string]*rateWindow{}
+			rateMutx.Unlock()
+			for i, key := range tt.keys {
+				count, reset, err := countRate(tt.name+":"+key, window)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if count != tt.want[i] {
+					t.Errorf("request %d of %s: got count %d, want %d", i, key, count, tt.want[i])
+				}
+				if want := time.Now().Truncate(window).Add(window); !reset.Equal(want) {
+					t.Errorf("got reset %v, want %v", reset, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCountRateWindowReset(t *testing.T) {
+	if config.Config.RedisAvaliable {
+		t.Skip("counts are kept in Redis")
+	}
+	const window = 50 * time.Millisecond
+	// start at the beginning of a window so that the first two requests share it
+	time.Sleep(time.Until(time.Now().Truncate(window).Add(window)))
+	for i, want := range []int64{1, 2} {
+		if count, _, _ := countRate("reset", window); count != want {
+			t.Fatalf("request %d: got count %d, want %d", i, count, want)
+		}
+	}
+	_, reset, _ := countRate("reset", window)
+	time.Sleep(time.Until(reset))
+	if count, _, _ := countRate("reset", window); count != 1 {
+		t.Errorf("got count %d in the next window, want 1", count)
+	}
+}
+
+func TestLookupAPIKeyRejectsMalformedKeys(t *testing.T) {
+	// none of these may reach the database
+	tests := []string{
+		"",
+		"secret",
+		"Bearer pgdb_0123",
+		"pgdb_" + strings.Repeat("0", 47),
+		"pgdb_" + strings.Repeat("0", 49),
+		"pgdb_" + strings.Repeat("z", 48),
+		"gdbp_" + strings.Repeat("0", 48),
+	}
+	for _, key := range tests {
+		t.Run(key, func(t *testing.T) {
+			if got := lookupAPIKey(key); got != nil {
+				t.Errorf("got %+v, want nil", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Route describes one endpoint, both the spec and the gin route are built from it
+type Route struct {
+	ID      string
+	Method  string
+	Path    string
+	Summary string
+	Tags    []string
+	Auth    bool
+	// Scopes the API key needs, any key is accepted when empty
+	Scopes []string
+	// RateLimit is the rate limit bucket of the route, not limited when empty
+	RateLimit string
+	// Cache is how long responses are cached, not cached when 0
+	Cache time.Duration
+	// CacheTags invalidate the cached responses besides the documents they contain
+	CacheTags []string
+	Params    []any
+	Body      any
+	Response  any
+	Handler   gin.HandlerFunc
+}
+
+var ginParamRegex = regexp.MustCompile(`[:*]([^/]+)`)
+
+// SpecPath converts the gin path of the route to an OpenAPI path template
+func (r *Route) SpecPath() string {
+	return ginParamRegex.ReplaceAllString(r.Path, "{$1}")
+}
+
+const securitySchemeName = "BearerAuth"
+
+// Generate builds the OpenAPI document for routes
+func Generate(info Info, serverURL string, routes []Route) *Document {
+	g := &generator{schemas: make(map[string]*Schema)}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Servers: []Server{{URL: serverURL}},
+		Paths:   make(map[string]PathItem),
+	}
+	hasAuth := false
+	for i := range routes {
+		route := &routes[i]
+		op := &Operation{
+			OperationID: route.ID,
+			Summary:     route.Summary,
+			Tags:        route.Tags,
+			Responses:   make(map[string]*Response),
+		}
+		for _, params := range route.Params {
+			op.Parameters = append(op.Parameters, g.parameters(reflect.TypeOf(params))...)
+		}
+		if route.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: g.schema(reflect.TypeOf(route.Body))}},
+			}
+		}
+		codes := []int{http.StatusOK, http.StatusBadRequest, http.StatusInternalServerError}
+		if route.Auth {
+			hasAuth = true
+			op.Security = []map[string][]string{{securitySchemeName: {}}}
+			codes = append(codes, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests)
+			if len(route.Scopes) > 0 {
+				op.Description = "Requires an API key with scope " + strings.Join(route.Scopes, ", ") + " or admin."
+			}
+		}
+		if route.RateLimit != "" && !slices.Contains(codes, http.StatusTooManyRequests) {
+			codes = append(codes, http.StatusTooManyRequests)
+		}
+		if route.Cache > 0 {
+			op.Responses[strconv.Itoa(http.StatusNotModified)] = &Response{Description: http.StatusText(http.StatusNotModified)}
+		}
+		content := map[string]*MediaType{"application/json": {Schema: g.schema(reflect.TypeOf(route.Response))}}
+		for _, code := range codes {
+			op.Responses[strconv.Itoa(code)] = &Response{Description: http.StatusText(code), Content: content}
+		}
+		path := route.SpecPath()
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+	doc.Components.Schemas = g.schemas
+	if hasAuth {
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			securitySchemeName: {Type: "http", Scheme: "bearer"},
+		}
+	}
+	return doc
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// parameters returns the path and query parameters bound from the uri and form tags of t
+func (g *generator) parameters(t reflect.Type) []*Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			params = append(params, g.parameters(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name := field.Tag.Get("uri"); name != "" {
+			params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: g.schema(field.Type)})
+		} else if name := field.Tag.Get("form"); name != "" && name != "-" {
+			params = append(params, &Parameter{Name: name, In: "query", Required: isRequired(field), Schema: g.schema(field.Type)})
+		}
+	}
+	return params
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case objectIDType:
+		return &Schema{Type: "string", Nullable: nullable}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem()), Nullable: nullable}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem()), Nullable: nullable}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// reserve the name first so recursive types terminate
+			g.schemas[name] = &Schema{}
+			g.schemas[name] = g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+// object builds an object schema from the json tags of t, embedded structs are flattened.
+// Only fields validated as required are listed as required, responses may omit any field.
+func (g *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			name, opts, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				continue
+			}
+			if field.Anonymous && name == "" {
+				if embedded := field.Type; embedded.Kind() == reflect.Struct {
+					addFields(embedded)
+					continue
+				} else if embedded.Kind() == reflect.Pointer && embedded.Elem().Kind() == reflect.Struct {
+					addFields(embedded.Elem())
+					continue
+				}
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			schema.Properties[name] = g.schema(field.Type)
+			// nil slices and maps are encoded as null unless they are omitted
+			if kind := field.Type.Kind(); (kind == reflect.Slice || kind == reflect.Map) && !slices.Contains(strings.Split(opts, ","), "omitempty") {
+				schema.Properties[name].Nullable = true
+			}
+			if isRequired(field) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	addFields(t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// isRequired reports whether gin validation requires the field to be set
+func isRequired(field reflect.StructField) bool {
+	return strings.Contains(","+field.Tag.Get("binding")+",", ",required,")
+}
+
+// schemaName turns generic type names like ListResponse[*model.GameInfo] into ListResponseGameInfo
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.IndexByte(name, '[')
+	if i < 0 {
+		return name
+	}
+	var builder strings.Builder
+	builder.WriteString(name[:i])
+	for _, arg := range strings.Split(name[i+1:len(name)-1], ",") {
+		arg = strings.TrimLeft(arg, "*[]")
+		if j := strings.LastIndexByte(arg, '.'); j >= 0 {
+			arg = arg[j+1:]
+		}
+		runes := []rune(arg)
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
@@ -0,0 +1,8 @@
+package openapi
+
+import _ "embed"
+
+// V2 is the committed OpenAPI 3 spec of the /v2 routes, generated by `pcgamedb openapi`
+//
+//go:embed v2.json
+var V2 []byte
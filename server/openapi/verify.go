@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Verify checks the contract between doc, the routes it was generated from and the routes registered on gin.
+// spec is the committed spec, it must match the generated doc byte for byte.
+// Registered routes under prefix must all be in the spec, except the ignored paths.
+func Verify(doc *Document, spec []byte, routes []Route, registered gin.RoutesInfo, prefix string, ignore ...string) []error {
+	var errs []error
+
+	generated, err := Marshal(doc)
+	if err != nil {
+		return []error{err}
+	}
+	if !bytes.Equal(bytes.TrimSpace(generated), bytes.TrimSpace(spec)) {
+		errs = append(errs, fmt.Errorf("committed spec is out of date, regenerate it with `pcgamedb openapi`"))
+	}
+
+	ops := make(map[string]bool)
+	ids := make(map[string]bool)
+	for i := range routes {
+		route := &routes[i]
+		key := strings.ToUpper(route.Method) + " " + route.SpecPath()
+		if ids[route.ID] {
+			errs = append(errs, fmt.Errorf("%s: duplicate operationId %s", key, route.ID))
+		}
+		ids[route.ID] = true
+		ops[key] = true
+		if route.Handler == nil {
+			errs = append(errs, fmt.Errorf("%s: no handler", key))
+		}
+		if route.Response == nil {
+			errs = append(errs, fmt.Errorf("%s: no response type", key))
+		}
+		item, ok := doc.Paths[route.SpecPath()]
+		if !ok || item[strings.ToLower(route.Method)] == nil {
+			errs = append(errs, fmt.Errorf("%s: missing from spec", key))
+			continue
+		}
+		errs = append(errs, verifyPathParams(key, route.SpecPath(), item[strings.ToLower(route.Method)])...)
+	}
+
+	ignored := make(map[string]bool)
+	for _, path := range ignore {
+		ignored[prefix+path] = true
+	}
+	served := make(map[string]bool)
+	for _, info := range registered {
+		if !strings.HasPrefix(info.Path, prefix+"/") || ignored[info.Path] {
+			continue
+		}
+		route := Route{Method: info.Method, Path: strings.TrimPrefix(info.Path, prefix)}
+		served[info.Method+" "+route.SpecPath()] = true
+	}
+	for key := range ops {
+		if !served[key] {
+			errs = append(errs, fmt.Errorf("%s: in spec but not registered under %s", key, prefix))
+		}
+	}
+	for key := range served {
+		if !ops[key] {
+			errs = append(errs, fmt.Errorf("%s: registered under %s but not in spec", key, prefix))
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// verifyPathParams checks that the path parameters of op are exactly the ones of the path template
+func verifyPathParams(key string, path string, op *Operation) []error {
+	var errs []error
+	want := make(map[string]bool)
+	for _, match := range ginParamRegex.FindAllStringSubmatch(strings.NewReplacer("{", ":", "}", "").Replace(path), -1) {
+		want[match[1]] = true
+	}
+	got := make(map[string]bool)
+	for _, param := range op.Parameters {
+		if param.In != "path" {
+			continue
+		}
+		got[param.Name] = true
+		if !want[param.Name] {
+			errs = append(errs, fmt.Errorf("%s: path parameter %s is not in the path", key, param.Name))
+		}
+	}
+	for name := range want {
+		if !got[name] {
+			errs = append(errs, fmt.Errorf("%s: path parameter %s is not bound by the handler", key, name))
+		}
+	}
+	return errs
+}
+
+// Marshal encodes doc the same way the committed spec is written
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,6 +1,10 @@
 package server
 
 import (
+	"time"
+
+	"github.com/nitezs/pcgamedb/cache"
+	"github.com/nitezs/pcgamedb/model"
 	"github.com/nitezs/pcgamedb/server/handler"
 	"github.com/nitezs/pcgamedb/server/middleware"
 
@@ -13,6 +17,14 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Cached responses are invalidated when the documents they contain change,
+// lists also when any document of their collection changes
+const (
+	detailCacheTTL = 10 * time.Minute
+	listCacheTTL   = time.Minute
+)
+
+// initRoute registers the unversioned v1 routes, kept for compatibility, and the /v2 routes
 func initRoute(app *gin.Engine) {
 	app.Use(cors.New(cors.Config{
 		AllowAllOrigins: true,
@@ -21,24 +33,73 @@ func initRoute(app *gin.Engine) {
 	GameInfoGroup := app.Group("/game")
 	GameItemGroup := GameInfoGroup.Group("/raw")
 
-	GameItemGroup.GET("/unorganized", handler.GetUnorganizedGameItemsHandler)
-	GameItemGroup.POST("/organize", middleware.Auth(), handler.OrganizeGameItemHandler)
-	GameItemGroup.GET("/id/:id", handler.GetGameItemByIDHanlder)
-	GameItemGroup.GET("/name/:name", handler.GetGameItemByRawNameHandler)
-	GameItemGroup.GET("/author/:author", handler.GetGameItemsByAuthorHandler)
+	GameItemGroup.GET("/unorganized", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(listCacheTTL, cache.TagGameItems, cache.TagGameInfos), handler.GetUnorganizedGameItemsHandler)
+	GameItemGroup.POST("/organize", middleware.Auth(model.ScopeOrganize), handler.OrganizeGameItemHandler)
+	GameItemGroup.GET("/id/:id", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(detailCacheTTL), handler.GetGameItemByIDHanlder)
+	GameItemGroup.GET("/name/:name", middleware.RateLimit(middleware.RateLimitSearch), middleware.Cache(listCacheTTL, cache.TagGameItems), handler.GetGameItemByRawNameHandler)
+	GameItemGroup.GET("/author/:author", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(listCacheTTL, cache.TagGameItems), handler.GetGameItemsByAuthorHandler)
 
-	GameInfoGroup.GET("/search", handler.SearchGamesHandler)
-	GameInfoGroup.GET("/name/:name", handler.GetGameInfosByNameHandler)
-	GameInfoGroup.GET("/platform/:platform_type/:platform_id", handler.GetGameInfoByPlatformIDHandler)
-	GameInfoGroup.GET("/id/:id", handler.GetGameInfoByIDHandler)
-	GameInfoGroup.PUT("/update", middleware.Auth(), handler.UpdateGameInfoHandler)
-	GameInfoGroup.DELETE("/id/:id", middleware.Auth(), handler.DeleteGameInfoHandler)
+	GameInfoGroup.GET("/search", middleware.RateLimit(middleware.RateLimitSearch), middleware.Cache(listCacheTTL, cache.TagGameInfos), handler.SearchGamesHandler)
+	GameInfoGroup.GET("/suggest", middleware.RateLimit(middleware.RateLimitSearch), middleware.Cache(listCacheTTL, cache.TagGameInfos), handler.SuggestGamesHandler)
+	GameInfoGroup.GET("/name/:name", middleware.RateLimit(middleware.RateLimitSearch), middleware.Cache(listCacheTTL, cache.TagGameInfos), handler.GetGameInfosByNameHandler)
+	GameInfoGroup.GET("/platform/:platform_type/:platform_id", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(detailCacheTTL), handler.GetGameInfoByPlatformIDHandler)
+	GameInfoGroup.GET("/id/:id", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(detailCacheTTL), handler.GetGameInfoByIDHandler)
+	GameInfoGroup.POST("/batch", middleware.RateLimit(middleware.RateLimitDetail), handler.GetGameInfosByBatchHandler)
+	GameInfoGroup.PUT("/update", middleware.Auth(model.ScopeOrganize), handler.UpdateGameInfoHandler)
+	GameInfoGroup.DELETE("/id/:id", middleware.Auth(model.ScopeDelete), handler.DeleteGameInfoHandler)
 
-	app.GET("/ranking/:type", handler.GetRankingHandler)
+	app.GET("/ranking/:type", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(listCacheTTL, cache.TagGameInfos), handler.GetRankingHandler)
 	app.GET("/healthcheck", handler.HealthCheckHandler)
-	app.GET("/author", handler.GetAllAuthorsHandler)
-	app.POST("/clean", middleware.Auth(), handler.CleanGameHandler)
+	app.GET("/author", middleware.RateLimit(middleware.RateLimitDetail), middleware.Cache(listCacheTTL, cache.TagGameItems), handler.GetAllAuthorsHandler)
+	app.POST("/clean", middleware.Auth(model.ScopeClean), handler.CleanGameHandler)
+	app.POST("/graphql", middleware.RateLimit(middleware.RateLimitSearch), handler.GraphQLHandler)
+
+	app.GET("/events", middleware.RateLimit(middleware.RateLimitDetail), middleware.LimitConnections(), handler.EventsHandler)
+	app.GET("/events/ws", middleware.RateLimit(middleware.RateLimitDetail), middleware.LimitConnections(), handler.EventsWebSocketHandler)
+
+	WebhookGroup := app.Group("/webhook", middleware.Auth(model.ScopeAdmin))
+	WebhookGroup.GET("", handler.GetWebhooksHandler)
+	WebhookGroup.POST("", handler.CreateWebhookHandler)
+	WebhookGroup.PUT("/:id", handler.UpdateWebhookHandler)
+	WebhookGroup.DELETE("/:id", handler.DeleteWebhookHandler)
+	WebhookGroup.GET("/:id/deliveries", handler.GetWebhookDeliveriesHandler)
+	WebhookGroup.POST("/:id/deliveries/:delivery_id/redeliver", handler.RedeliverWebhookHandler)
+
+	NotifierGroup := app.Group("/notifier")
+	NotifierGroup.GET("", middleware.Auth(model.ScopeRead), handler.GetNotifiersHandler)
+	NotifierGroup.POST("", middleware.Auth(model.ScopeNotify), handler.CreateNotifierHandler)
+	NotifierGroup.PUT("/:id", middleware.Auth(model.ScopeNotify), handler.UpdateNotifierHandler)
+	NotifierGroup.DELETE("/:id", middleware.Auth(model.ScopeNotify), handler.DeleteNotifierHandler)
+	NotifierGroup.POST("/:id/test", middleware.Auth(model.ScopeNotify), handler.TestNotifierHandler)
 
-	docs.SwaggerInfo.BasePath = "/api"
+	WatchlistGroup := app.Group("/watchlist")
+	WatchlistGroup.GET("", middleware.Auth(model.ScopeRead), handler.GetWatchlistHandler)
+	WatchlistGroup.POST("", middleware.Auth(model.ScopeNotify), handler.AddWatchlistEntryHandler)
+	WatchlistGroup.DELETE("/:id", middleware.Auth(model.ScopeNotify), handler.DeleteWatchlistEntryHandler)
+
+	ReviewGroup := app.Group("/review", middleware.Auth(model.ScopeOrganize))
+	ReviewGroup.GET("", handler.GetReviewsHandler)
+	ReviewGroup.POST("/:id/accept", handler.AcceptReviewHandler)
+	ReviewGroup.POST("/:id/reject", handler.RejectReviewHandler)
+
+	AliasGroup := app.Group("/alias", middleware.Auth(model.ScopeOrganize))
+	AliasGroup.GET("", handler.GetAliasesHandler)
+	AliasGroup.POST("", handler.ImportAliasesHandler)
+	AliasGroup.DELETE("", handler.DeleteAliasHandler)
+
+	CacheGroup := app.Group("/cache", middleware.Auth(model.ScopeAdmin))
+	CacheGroup.GET("/negative", handler.GetNegativeCacheHandler)
+	CacheGroup.DELETE("/negative", handler.ClearNegativeCacheHandler)
+
+	FeedGroup := app.Group("/feed", middleware.RateLimit(middleware.RateLimitDetail))
+	FeedGroup.GET("/latest.atom", handler.GetLatestFeedHandler("atom"))
+	FeedGroup.GET("/latest.rss", handler.GetLatestFeedHandler("rss"))
+	FeedGroup.GET("/latest.json", handler.GetLatestFeedHandler("json"))
+	FeedGroup.GET("/author/:author", handler.GetAuthorFeedHandler)
+	FeedGroup.GET("/game/:id", handler.GetGameFeedHandler)
+
+	docs.SwaggerInfo.BasePath = "/"
 	app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+
+	initV2Route(app)
 }
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nitezs/pcgamedb/server/handler"
+)
+
+// v1ListShapes pins the fields of the list responses of the unversioned routes,
+// they must not change for the clients written against them
+var v1ListShapes = []struct {
+	path     string
+	handler  string
+	response any
+	fields   []string
+}{
+	{path: "/author", handler: "GetAllAuthorsHandler", response: handler.GetAllAuthorsResponse{}, fields: []string{"authors", "message", "status"}},
+	{path: "/game/search", handler: "SearchGamesHandler", response: handler.SearchGamesResponse{}, fields: []string{"facets", "game_infos", "message", "status", "total_page"}},
+	{path: "/game/name/:name", handler: "GetGameInfosByNameHandler", response: handler.GetGameInfosByNameResponse{}, fields: []string{"game_infos", "message", "status"}},
+	{path: "/game/raw/name/:name", handler: "GetGameItemByRawNameHandler", response: handler.GetGameItemByRawNameResponse{}, fields: []string{"game_downloads", "message", "status"}},
+	{path: "/game/raw/author/:author", handler: "GetGameItemsByAuthorHandler", response: handler.GetGameItemsByAuthorResponse{}, fields: []string{"game_downloads", "message", "status", "total_page"}},
+	{path: "/game/raw/unorganized", handler: "GetUnorganizedGameItemsHandler", response: handler.GetUnorganizedGameItemsResponse{}, fields: []string{"game_downloads", "message", "size", "status"}},
+	{path: "/ranking/:type", handler: "GetRankingHandler", response: handler.GetRankingResponse{}, fields: []string{"games", "message", "status"}},
+	{path: "/game/suggest", handler: "SuggestGamesHandler", response: handler.SuggestGamesResponse{}, fields: []string{"message", "status", "suggestions"}},
+}
+
+func TestV1ListShapes(t *testing.T) {
+	app := newTestApp(t)
+	handlers := make(map[string]string)
+	for _, route := range app.Routes() {
+		if route.Method == http.MethodGet {
+			handlers[route.Path] = route.Handler
+		}
+	}
+	for _, tt := range v1ListShapes {
+		t.Run(tt.path, func(t *testing.T) {
+			if name := handlers[tt.path]; !strings.HasSuffix(name, "handler."+tt.handler) {
+				t.Errorf("got handler %s, want %s", name, tt.handler)
+			}
+			data, err := json.Marshal(sampleValue(reflect.TypeOf(tt.response), 0).Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(fields))
+			for field := range fields {
+				got = append(got, field)
+			}
+			sort.Strings(got)
+			if !slices.Equal(got, tt.fields) {
+				t.Errorf("got fields %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestV1SearchError(t *testing.T) {
+	app := newTestApp(t)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/game/search?keyword=ab", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 2 || body["status"] != "error" || body["message"] == nil {
+		t.Errorf("got %s, want only status and message", w.Body)
+	}
+}
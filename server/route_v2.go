@@ -0,0 +1,335 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/nitezs/pcgamedb/cache"
+
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/server/handler"
+	"github.com/nitezs/pcgamedb/server/middleware"
+	"github.com/nitezs/pcgamedb/server/openapi"
+
+	"github.com/gin-gonic/gin"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+const v2Prefix = "/v2"
+
+// v2DocRoutes are served under /v2 but are not part of the API
+var v2DocRoutes = []string{"/openapi.json", "/swagger/*any"}
+
+// v2Routes is the source of the /v2 routes and of the committed OpenAPI spec.
+// Run `pcgamedb openapi` after changing it or any request/response type it references.
+var v2Routes = []openapi.Route{
+	{
+		ID: "getUnorganizedGameItems", Method: http.MethodGet, Path: "/game/raw/unorganized",
+		Summary: "List unorganized game items", Tags: []string{"game"}, RateLimit: middleware.RateLimitDetail, Cache: listCacheTTL, CacheTags: []string{cache.TagGameItems, cache.TagGameInfos},
+		Params:   []any{handler.GetUnorganizedGameItemsRequest{}},
+		Response: handler.GetUnorganizedGameItemsListResponse{},
+		Handler:  handler.GetUnorganizedGameItemsListHandler,
+	},
+	{
+		ID: "organizeGameItem", Method: http.MethodPost, Path: "/game/raw/organize",
+		Summary: "Organize a game item manually", Tags: []string{"game"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Body:     handler.OrganizeGameItemRequest{},
+		Response: handler.OrganizeGameItemResponse{},
+		Handler:  handler.OrganizeGameItemHandler,
+	},
+	{
+		ID: "getGameItemByID", Method: http.MethodGet, Path: "/game/raw/id/:id",
+		Summary: "Retrieve a game item by ID", Tags: []string{"game"}, RateLimit: middleware.RateLimitDetail, Cache: detailCacheTTL,
+		Params:   []any{handler.GetGameItemByIDRequest{}},
+		Response: handler.GetGameItemByIDResponse{},
+		Handler:  handler.GetGameItemByIDHanlder,
+	},
+	{
+		ID: "getGameItemsByRawName", Method: http.MethodGet, Path: "/game/raw/name/:name",
+		Summary: "Retrieve game items by raw name", Tags: []string{"game"}, RateLimit: middleware.RateLimitSearch, Cache: listCacheTTL, CacheTags: []string{cache.TagGameItems},
+		Params:   []any{handler.GetGameItemByRawNameRequest{}},
+		Response: handler.GetGameItemByRawNameListResponse{},
+		Handler:  handler.GetGameItemByRawNameListHandler,
+	},
+	{
+		ID: "getGameItemsByAuthor", Method: http.MethodGet, Path: "/game/raw/author/:author",
+		Summary: "Retrieve game items by author", Tags: []string{"game"}, RateLimit: middleware.RateLimitDetail, Cache: listCacheTTL, CacheTags: []string{cache.TagGameItems},
+		Params:   []any{handler.GetGameItemsByAuthorRequest{}},
+		Response: handler.GetGameItemsByAuthorListResponse{},
+		Handler:  handler.GetGameItemsByAuthorListHandler,
+	},
+	{
+		ID: "searchGames", Method: http.MethodGet, Path: "/game/search",
+		Summary: "Search game infos", Tags: []string{"game"}, RateLimit: middleware.RateLimitSearch, Cache: listCacheTTL, CacheTags: []string{cache.TagGameInfos},
+		Params:   []any{handler.SearchGamesRequest{}},
+		Response: handler.SearchGamesListResponse{},
+		Handler:  handler.SearchGamesListHandler,
+	},
+	{
+		ID: "suggestGames", Method: http.MethodGet, Path: "/game/suggest",
+		Summary: "Suggest game names", Tags: []string{"game"}, RateLimit: middleware.RateLimitSearch, Cache: listCacheTTL, CacheTags: []string{cache.TagGameInfos},
+		Params:   []any{handler.SuggestGamesRequest{}},
+		Response: handler.SuggestGamesListResponse{},
+		Handler:  handler.SuggestGamesListHandler,
+	},
+	{
+		ID: "getGameInfosByName", Method: http.MethodGet, Path: "/game/name/:name",
+		Summary: "Retrieve game infos by name", Tags: []string{"game"}, RateLimit: middleware.RateLimitSearch, Cache: listCacheTTL, CacheTags: []string{cache.TagGameInfos},
+		Params:   []any{handler.GetGameInfosByNameRequest{}},
+		Response: handler.GetGameInfosByNameListResponse{},
+		Handler:  handler.GetGameInfosByNameListHandler,
+	},
+	{
+		ID: "getGameInfoByPlatformID", Method: http.MethodGet, Path: "/game/platform/:platform_type/:platform_id",
+		Summary: "Retrieve a game info by platform ID", Tags: []string{"game"}, RateLimit: middleware.RateLimitDetail, Cache: detailCacheTTL,
+		Params:   []any{handler.GetGameInfoByPlatformIDRequest{}},
+		Response: handler.GetGameInfoByPlatformIDResponse{},
+		Handler:  handler.GetGameInfoByPlatformIDHandler,
+	},
+	{
+		ID: "getGameInfoByID", Method: http.MethodGet, Path: "/game/id/:id",
+		Summary: "Retrieve a game info by ID", Tags: []string{"game"}, RateLimit: middleware.RateLimitDetail, Cache: detailCacheTTL,
+		Params:   []any{handler.GetGameInfoByIDRequest{}, handler.GetGameInfoByIDQuery{}},
+		Response: handler.GetGameInfoByIDResponse{},
+		Handler:  handler.GetGameInfoByIDHandler,
+	},
+	{
+		ID: "getGameInfosByBatch", Method: http.MethodPost, Path: "/game/batch",
+		Summary: "Retrieve game infos by IDs, Steam IDs or IGDB IDs", Tags: []string{"game"}, RateLimit: middleware.RateLimitDetail,
+		Params:   []any{handler.GetGameInfosByBatchQuery{}},
+		Body:     handler.GetGameInfosByBatchRequest{},
+		Response: handler.GetGameInfosByBatchResponse{},
+		Handler:  handler.GetGameInfosByBatchHandler,
+	},
+	{
+		ID: "updateGameInfo", Method: http.MethodPut, Path: "/game/update",
+		Summary: "Update a game info from a platform", Tags: []string{"game"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Body:     handler.UpdateGameInfoRequest{},
+		Response: handler.UpdateGameInfoResponse{},
+		Handler:  handler.UpdateGameInfoHandler,
+	},
+	{
+		ID: "deleteGameInfo", Method: http.MethodDelete, Path: "/game/id/:id",
+		Summary: "Delete a game info", Tags: []string{"game"}, Auth: true, Scopes: []string{model.ScopeDelete},
+		Params:   []any{handler.DeleteGameInfoRequest{}},
+		Response: handler.DeleteGameInfoResponse{},
+		Handler:  handler.DeleteGameInfoHandler,
+	},
+	{
+		ID: "getRanking", Method: http.MethodGet, Path: "/ranking/:type",
+		Summary: "Retrieve a ranking", Tags: []string{"ranking"}, RateLimit: middleware.RateLimitDetail, Cache: listCacheTTL, CacheTags: []string{cache.TagGameInfos},
+		Params:   []any{handler.GetRankingRequest{}},
+		Response: handler.GetRankingListResponse{},
+		Handler:  handler.GetRankingListHandler,
+	},
+	{
+		ID: "healthCheck", Method: http.MethodGet, Path: "/healthcheck",
+		Summary: "Health check", Tags: []string{"health"},
+		Response: handler.HealthCheckResponse{},
+		Handler:  handler.HealthCheckHandler,
+	},
+	{
+		ID: "getAllAuthors", Method: http.MethodGet, Path: "/author",
+		Summary: "List all authors", Tags: []string{"author"}, RateLimit: middleware.RateLimitDetail, Cache: listCacheTTL, CacheTags: []string{cache.TagGameItems},
+		Params:   []any{handler.GetAllAuthorsRequest{}},
+		Response: handler.GetAllAuthorsListResponse{},
+		Handler:  handler.GetAllAuthorsListHandler,
+	},
+	{
+		ID: "cleanGames", Method: http.MethodPost, Path: "/clean",
+		Summary: "Clean invalid games", Tags: []string{"game"}, Auth: true, Scopes: []string{model.ScopeClean},
+		Response: handler.CleanGameResponse{},
+		Handler:  handler.CleanGameHandler,
+	},
+	{
+		ID: "getWebhooks", Method: http.MethodGet, Path: "/webhook",
+		Summary: "List webhooks", Tags: []string{"webhook"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Response: handler.GetWebhooksResponse{},
+		Handler:  handler.GetWebhooksHandler,
+	},
+	{
+		ID: "createWebhook", Method: http.MethodPost, Path: "/webhook",
+		Summary: "Create a webhook", Tags: []string{"webhook"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Body:     handler.CreateWebhookRequest{},
+		Response: handler.WebhookResponse{},
+		Handler:  handler.CreateWebhookHandler,
+	},
+	{
+		ID: "updateWebhook", Method: http.MethodPut, Path: "/webhook/:id",
+		Summary: "Update a webhook", Tags: []string{"webhook"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Params:   []any{handler.WebhookIDRequest{}},
+		Body:     handler.UpdateWebhookRequest{},
+		Response: handler.WebhookResponse{},
+		Handler:  handler.UpdateWebhookHandler,
+	},
+	{
+		ID: "deleteWebhook", Method: http.MethodDelete, Path: "/webhook/:id",
+		Summary: "Delete a webhook", Tags: []string{"webhook"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Params:   []any{handler.WebhookIDRequest{}},
+		Response: handler.WebhookResponse{},
+		Handler:  handler.DeleteWebhookHandler,
+	},
+	{
+		ID: "getWebhookDeliveries", Method: http.MethodGet, Path: "/webhook/:id/deliveries",
+		Summary: "List webhook deliveries", Tags: []string{"webhook"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Params:   []any{handler.GetWebhookDeliveriesRequest{}},
+		Response: handler.GetWebhookDeliveriesResponse{},
+		Handler:  handler.GetWebhookDeliveriesHandler,
+	},
+	{
+		ID: "redeliverWebhook", Method: http.MethodPost, Path: "/webhook/:id/deliveries/:delivery_id/redeliver",
+		Summary: "Redeliver a webhook delivery", Tags: []string{"webhook"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Params:   []any{handler.RedeliverWebhookRequest{}},
+		Response: handler.RedeliverWebhookResponse{},
+		Handler:  handler.RedeliverWebhookHandler,
+	},
+	{
+		ID: "getNotifiers", Method: http.MethodGet, Path: "/notifier",
+		Summary: "List notifiers", Tags: []string{"notifier"}, Auth: true, Scopes: []string{model.ScopeRead},
+		Response: handler.GetNotifiersResponse{},
+		Handler:  handler.GetNotifiersHandler,
+	},
+	{
+		ID: "createNotifier", Method: http.MethodPost, Path: "/notifier",
+		Summary: "Create a Telegram, Discord or Matrix notifier", Tags: []string{"notifier"}, Auth: true, Scopes: []string{model.ScopeNotify},
+		Body:     handler.CreateNotifierRequest{},
+		Response: handler.NotifierResponse{},
+		Handler:  handler.CreateNotifierHandler,
+	},
+	{
+		ID: "updateNotifier", Method: http.MethodPut, Path: "/notifier/:id",
+		Summary: "Update a notifier", Tags: []string{"notifier"}, Auth: true, Scopes: []string{model.ScopeNotify},
+		Params:   []any{handler.NotifierIDRequest{}},
+		Body:     handler.UpdateNotifierRequest{},
+		Response: handler.NotifierResponse{},
+		Handler:  handler.UpdateNotifierHandler,
+	},
+	{
+		ID: "deleteNotifier", Method: http.MethodDelete, Path: "/notifier/:id",
+		Summary: "Delete a notifier", Tags: []string{"notifier"}, Auth: true, Scopes: []string{model.ScopeNotify},
+		Params:   []any{handler.NotifierIDRequest{}},
+		Response: handler.NotifierResponse{},
+		Handler:  handler.DeleteNotifierHandler,
+	},
+	{
+		ID: "testNotifier", Method: http.MethodPost, Path: "/notifier/:id/test",
+		Summary: "Send the latest game item to a notifier", Tags: []string{"notifier"}, Auth: true, Scopes: []string{model.ScopeNotify},
+		Params:   []any{handler.NotifierIDRequest{}},
+		Response: handler.NotifierResponse{},
+		Handler:  handler.TestNotifierHandler,
+	},
+	{
+		ID: "getWatchlist", Method: http.MethodGet, Path: "/watchlist",
+		Summary: "List the watched games", Tags: []string{"watchlist"}, Auth: true, Scopes: []string{model.ScopeRead},
+		Response: handler.GetWatchlistResponse{},
+		Handler:  handler.GetWatchlistHandler,
+	},
+	{
+		ID: "addWatchlistEntry", Method: http.MethodPost, Path: "/watchlist",
+		Summary: "Watch a game", Tags: []string{"watchlist"}, Auth: true, Scopes: []string{model.ScopeNotify},
+		Body:     handler.AddWatchlistEntryRequest{},
+		Response: handler.AddWatchlistEntryResponse{},
+		Handler:  handler.AddWatchlistEntryHandler,
+	},
+	{
+		ID: "deleteWatchlistEntry", Method: http.MethodDelete, Path: "/watchlist/:id",
+		Summary: "Unwatch a game", Tags: []string{"watchlist"}, Auth: true, Scopes: []string{model.ScopeNotify},
+		Params:   []any{handler.DeleteWatchlistEntryRequest{}},
+		Response: handler.DeleteWatchlistEntryResponse{},
+		Handler:  handler.DeleteWatchlistEntryHandler,
+	},
+	{
+		ID: "getReviews", Method: http.MethodGet, Path: "/review",
+		Summary: "List the review queue", Tags: []string{"review"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Params:   []any{handler.GetReviewsRequest{}},
+		Response: handler.GetReviewsResponse{},
+		Handler:  handler.GetReviewsHandler,
+	},
+	{
+		ID: "acceptReview", Method: http.MethodPost, Path: "/review/:id/accept",
+		Summary: "Accept a review", Tags: []string{"review"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Params:   []any{handler.ReviewIDRequest{}},
+		Body:     handler.AcceptReviewRequest{},
+		Response: handler.ReviewResponse{},
+		Handler:  handler.AcceptReviewHandler,
+	},
+	{
+		ID: "rejectReview", Method: http.MethodPost, Path: "/review/:id/reject",
+		Summary: "Reject a review", Tags: []string{"review"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Params:   []any{handler.ReviewIDRequest{}},
+		Response: handler.ReviewResponse{},
+		Handler:  handler.RejectReviewHandler,
+	},
+	{
+		ID: "getAliases", Method: http.MethodGet, Path: "/alias",
+		Summary: "Export name aliases", Tags: []string{"alias"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Response: handler.GetAliasesResponse{},
+		Handler:  handler.GetAliasesHandler,
+	},
+	{
+		ID: "importAliases", Method: http.MethodPost, Path: "/alias",
+		Summary: "Import name aliases", Tags: []string{"alias"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Body:     handler.ImportAliasesRequest{},
+		Response: handler.ImportAliasesResponse{},
+		Handler:  handler.ImportAliasesHandler,
+	},
+	{
+		ID: "deleteAlias", Method: http.MethodDelete, Path: "/alias",
+		Summary: "Delete a name alias", Tags: []string{"alias"}, Auth: true, Scopes: []string{model.ScopeOrganize},
+		Params:   []any{handler.DeleteAliasRequest{}},
+		Response: handler.DeleteAliasResponse{},
+		Handler:  handler.DeleteAliasHandler,
+	},
+	{
+		ID: "getNegativeCache", Method: http.MethodGet, Path: "/cache/negative",
+		Summary: "Show failed ID lookups of a name", Tags: []string{"cache"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Params:   []any{handler.NegativeCacheRequest{}},
+		Response: handler.GetNegativeCacheResponse{},
+		Handler:  handler.GetNegativeCacheHandler,
+	},
+	{
+		ID: "clearNegativeCache", Method: http.MethodDelete, Path: "/cache/negative",
+		Summary: "Clear failed ID lookups of a name", Tags: []string{"cache"}, Auth: true, Scopes: []string{model.ScopeAdmin},
+		Params:   []any{handler.NegativeCacheRequest{}},
+		Response: handler.ClearNegativeCacheResponse{},
+		Handler:  handler.ClearNegativeCacheHandler,
+	},
+}
+
+func initV2Route(app *gin.Engine) {
+	group := app.Group(v2Prefix)
+	for _, route := range v2Routes {
+		handlers := []gin.HandlerFunc{}
+		if route.RateLimit != "" {
+			handlers = append(handlers, middleware.RateLimit(route.RateLimit))
+		}
+		if route.Auth {
+			handlers = append(handlers, middleware.Auth(route.Scopes...))
+		}
+		if route.Cache > 0 {
+			handlers = append(handlers, middleware.Cache(route.Cache, route.CacheTags...))
+		}
+		group.Handle(route.Method, route.Path, append(handlers, route.Handler)...)
+	}
+	group.GET(v2DocRoutes[0], func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.V2)
+	})
+	group.GET(v2DocRoutes[1], ginSwagger.WrapHandler(swaggerfiles.Handler, ginSwagger.URL(v2Prefix+"/openapi.json")))
+}
+
+// V2Spec generates the OpenAPI spec of the /v2 routes
+func V2Spec() *openapi.Document {
+	return openapi.Generate(openapi.Info{
+		Title:       "pcgamedb",
+		Description: "Repack game data collected by pcgamedb",
+		Version:     "2.0",
+	}, v2Prefix, v2Routes)
+}
+
+// VerifyV2 checks the committed spec and the registered /v2 routes against v2Routes
+func VerifyV2() []error {
+	gin.SetMode(gin.ReleaseMode)
+	app := gin.New()
+	initRoute(app)
+	return openapi.Verify(V2Spec(), openapi.V2, v2Routes, app.Routes(), v2Prefix, v2DocRoutes...)
+}
@@ -0,0 +1,349 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/server/middleware"
+	"github.com/nitezs/pcgamedb/server/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testSecretKey = "test-secret"
+
+// databaseRoutes always query the database, the tests run without one
+// so only the encoding of their responses is checked
+var databaseRoutes = []string{"healthCheck", "getGameItemsByRawName"}
+
+// v2Request is a request answered without the database, by the middlewares or the request validation
+type v2Request struct {
+	route  string
+	path   string
+	auth   bool
+	body   string
+	status int
+}
+
+var v2Requests = []v2Request{
+	{route: "getUnorganizedGameItems", path: "/game/raw/unorganized?cursor=!", status: http.StatusBadRequest},
+	{route: "organizeGameItem", path: "/game/raw/organize", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "getGameItemByID", path: "/game/raw/id/bad", status: http.StatusBadRequest},
+	{route: "getGameItemsByAuthor", path: "/game/raw/author/dodi?cursor=!", status: http.StatusBadRequest},
+	{route: "searchGames", path: "/game/search?fields=unknown", status: http.StatusBadRequest},
+	{route: "suggestGames", path: "/game/suggest", status: http.StatusBadRequest},
+	{route: "getGameInfosByName", path: "/game/name/hollow?view=unknown", status: http.StatusBadRequest},
+	{route: "getGameInfoByPlatformID", path: "/game/platform/steam/bad", status: http.StatusBadRequest},
+	{route: "getGameInfoByID", path: "/game/id/bad", status: http.StatusBadRequest},
+	{route: "getGameInfosByBatch", path: "/game/batch", body: `{"steam_ids":[0]}`, status: http.StatusBadRequest},
+	{route: "updateGameInfo", path: "/game/update", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "deleteGameInfo", path: "/game/id/bad", auth: true, status: http.StatusBadRequest},
+	{route: "getRanking", path: "/ranking/unknown", status: http.StatusBadRequest},
+	{route: "getAllAuthors", path: "/author?cursor=!", status: http.StatusBadRequest},
+	{route: "createWebhook", path: "/webhook", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "updateWebhook", path: "/webhook/bad", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "deleteWebhook", path: "/webhook/bad", auth: true, status: http.StatusBadRequest},
+	{route: "getWebhookDeliveries", path: "/webhook/bad/deliveries", auth: true, status: http.StatusBadRequest},
+	{route: "redeliverWebhook", path: "/webhook/bad/deliveries/bad/redeliver", auth: true, status: http.StatusBadRequest},
+	{route: "createNotifier", path: "/notifier", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "updateNotifier", path: "/notifier/bad", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "deleteNotifier", path: "/notifier/bad", auth: true, status: http.StatusBadRequest},
+	{route: "testNotifier", path: "/notifier/bad/test", auth: true, status: http.StatusBadRequest},
+	{route: "addWatchlistEntry", path: "/watchlist", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "deleteWatchlistEntry", path: "/watchlist/bad", auth: true, status: http.StatusBadRequest},
+	{route: "getReviews", path: "/review?status=unknown", auth: true, status: http.StatusBadRequest},
+	{route: "acceptReview", path: "/review/bad/accept", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "rejectReview", path: "/review/bad/reject", auth: true, status: http.StatusBadRequest},
+	{route: "importAliases", path: "/alias", auth: true, body: `{}`, status: http.StatusBadRequest},
+	{route: "deleteAlias", path: "/alias", auth: true, status: http.StatusBadRequest},
+	{route: "getNegativeCache", path: "/cache/negative", auth: true, status: http.StatusBadRequest},
+	{route: "clearNegativeCache", path: "/cache/negative", auth: true, status: http.StatusBadRequest},
+}
+
+func newTestApp(t *testing.T) *gin.Engine {
+	t.Helper()
+	oldSecretKey, oldWindow := config.Config.Server.SecretKey, config.Config.RateLimit.Window
+	config.Config.Server.SecretKey = testSecretKey
+	config.Config.RateLimit.Window = 0
+	t.Cleanup(func() {
+		config.Config.Server.SecretKey = oldSecretKey
+		config.Config.RateLimit.Window = oldWindow
+	})
+	gin.SetMode(gin.ReleaseMode)
+	app := gin.New()
+	app.Use(middleware.Recovery())
+	initRoute(app)
+	return app
+}
+
+func loadV2Spec(t *testing.T) *openapi.Document {
+	t.Helper()
+	var doc openapi.Document
+	if err := json.Unmarshal(openapi.V2, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return &doc
+}
+
+func findV2Route(t *testing.T, id string) *openapi.Route {
+	t.Helper()
+	for i := range v2Routes {
+		if v2Routes[i].ID == id {
+			return &v2Routes[i]
+		}
+	}
+	t.Fatalf("unknown route %s", id)
+	return nil
+}
+
+// responseSchema returns the schema the spec documents for status of route
+func responseSchema(t *testing.T, doc *openapi.Document, route *openapi.Route, status int) *openapi.Schema {
+	t.Helper()
+	op := doc.Paths[route.SpecPath()][strings.ToLower(route.Method)]
+	if op == nil {
+		t.Fatalf("%s %s is not in the spec", route.Method, route.SpecPath())
+	}
+	res, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		t.Fatalf("%s %s does not document status %d", route.Method, route.SpecPath(), status)
+	}
+	media, ok := res.Content["application/json"]
+	if !ok {
+		t.Fatalf("%s %s does not document a body for status %d", route.Method, route.SpecPath(), status)
+	}
+	return media.Schema
+}
+
+func TestV2Requests(t *testing.T) {
+	app := newTestApp(t)
+	doc := loadV2Spec(t)
+	requests := append([]v2Request{}, v2Requests...)
+	for _, route := range v2Routes {
+		if route.Auth {
+			requests = append(requests, v2Request{route: route.ID, path: route.Path, status: http.StatusUnauthorized})
+		}
+	}
+	for _, tt := range requests {
+		route := findV2Route(t, tt.route)
+		t.Run(fmt.Sprintf("%s %d", tt.route, tt.status), func(t *testing.T) {
+			req := httptest.NewRequest(route.Method, v2Prefix+tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer "+testSecretKey)
+			}
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			value, err := decodeJSON(w.Body.Bytes())
+			if err != nil {
+				t.Fatalf("body is not JSON: %s", w.Body)
+			}
+			if err := validateSchema(doc, responseSchema(t, doc, route, tt.status), value, "body"); err != nil {
+				t.Errorf("%v: %s", err, w.Body)
+			}
+			if status := value.(map[string]any)["status"]; status != "error" {
+				t.Errorf("got status %v, want error", status)
+			}
+		})
+	}
+}
+
+func TestV2RequestsCoverRoutes(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, id := range databaseRoutes {
+		covered[id] = true
+	}
+	for _, tt := range v2Requests {
+		covered[tt.route] = true
+	}
+	for _, route := range v2Routes {
+		// requests without an API key are answered by the auth middleware
+		if !covered[route.ID] && !route.Auth {
+			t.Errorf("%s: no request answered without the database, add one to v2Requests", route.ID)
+		}
+	}
+}
+
+// TestV2Responses checks that the responses of every route, filled with sample values, encode as the spec documents
+func TestV2Responses(t *testing.T) {
+	doc := loadV2Spec(t)
+	for i := range v2Routes {
+		route := &v2Routes[i]
+		t.Run(route.ID, func(t *testing.T) {
+			value := sampleValue(reflect.TypeOf(route.Response), 0)
+			data, err := json.Marshal(value.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := decodeJSON(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := validateSchema(doc, responseSchema(t, doc, route, http.StatusOK), decoded, "body"); err != nil {
+				t.Errorf("%v: %s", err, data)
+			}
+		})
+	}
+}
+
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	err := dec.Decode(&value)
+	return value, err
+}
+
+// validateSchema checks value against the subset of JSON schema the generator emits.
+// Objects must not have fields the schema does not document.
+func validateSchema(doc *openapi.Document, schema *openapi.Schema, value any, path string) error {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		ref, ok := doc.Components.Schemas[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", path, schema.Ref)
+		}
+		schema = ref
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not a %s", path, schema.Type)
+	}
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", path, value)
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing %s", path, name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := schema.Properties[key]
+			if !ok {
+				field = schema.AdditionalProperties
+			}
+			if field == nil {
+				return fmt.Errorf("%s: undocumented field %s", path, key)
+			}
+			if err := validateSchema(doc, field, obj[key], path+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, value)
+		}
+		for i, item := range arr {
+			if err := validateSchema(doc, schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", path, value)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", path, s)
+			}
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an integer", path, value)
+		}
+		if _, err := n.Int64(); err != nil {
+			return fmt.Errorf("%s: %v is not an integer", path, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s: %v is not a number", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", path, value)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported type %s", path, schema.Type)
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// sampleValue returns a value of t with every exported field set, slices and maps get one element.
+// Recursive types stop being filled after a few levels.
+func sampleValue(t reflect.Type, depth int) reflect.Value {
+	v := reflect.New(t).Elem()
+	if depth > 10 {
+		return v
+	}
+	switch t {
+	case timeType:
+		v.Set(reflect.ValueOf(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+		return v
+	case objectIDType:
+		v.Set(reflect.ValueOf(primitive.NewObjectID()))
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(t.Elem())
+		p.Elem().Set(sampleValue(t.Elem(), depth+1))
+		v.Set(p)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				v.Field(i).Set(sampleValue(t.Field(i).Type, depth+1))
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.Append(reflect.MakeSlice(t, 0, 1), sampleValue(t.Elem(), depth+1)))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		v.SetMapIndex(sampleValue(t.Key(), depth+1), sampleValue(t.Elem(), depth+1))
+	case reflect.String:
+		v.SetString("sample")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	}
+	return v
+}
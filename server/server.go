@@ -1,21 +1,27 @@
 package server
 
 import (
+	"context"
 	"io"
 	"time"
 
 	"github.com/nitezs/pcgamedb/cache"
 	"github.com/nitezs/pcgamedb/config"
 	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/events"
 	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/notify"
 	"github.com/nitezs/pcgamedb/server/middleware"
 	"github.com/nitezs/pcgamedb/task"
+	"github.com/nitezs/pcgamedb/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
+const nameIndexReloadInterval = 30 * time.Minute
+
 func init() {
 	config.Runtime.ServerStartTime = time.Now()
 }
@@ -23,12 +29,35 @@ func init() {
 func Run() {
 	db.CheckConnect()
 	cache.CheckConnect()
+	go func() {
+		if err := db.LoadGameInfoNameIndex(); err != nil {
+			log.Logger.Error("Failed to load game info name index", zap.Error(err))
+		}
+		// game infos saved or deleted by other processes, like crawls run from the command line,
+		// only reach the index when it is reloaded
+		for range time.Tick(nameIndexReloadInterval) {
+			if err := db.ReloadGameInfoNameIndex(); err != nil {
+				log.Logger.Error("Failed to reload game info name index", zap.Error(err))
+			}
+		}
+	}()
+	go events.ListenRelay(context.Background())
+	webhook.Init()
+	notify.Init()
+	go webhook.Run(context.Background())
 	gin.SetMode(gin.ReleaseMode)
 	gin.DefaultWriter = io.Discard
 	app := gin.New()
+	// the client IP rate limits are counted by must not come from a header anyone can send
+	if err := app.SetTrustedProxies(config.Config.Server.TrustedProxies); err != nil {
+		log.Logger.Panic("Invalid trusted proxies", zap.Error(err))
+	}
 	app.Use(middleware.Logger())
 	app.Use(middleware.Recovery())
 	initRoute(app)
+	if config.Config.Server.PublicURL == "" {
+		log.Logger.Warn("server.public_url is not set, feed links point at localhost unless the request comes from a trusted proxy")
+	}
 	log.Logger.Info("Server running", zap.String("port", config.Config.Server.Port))
 	if config.Config.Server.AutoCrawl {
 		go func() {
@@ -37,6 +66,10 @@ func Run() {
 			if err != nil {
 				log.Logger.Error("Error adding cron job", zap.Error(err))
 			}
+			_, err = c.AddFunc("30 4 * * *", func() { task.ImportSteamApps(log.TaskLogger) })
+			if err != nil {
+				log.Logger.Error("Error adding cron job", zap.Error(err))
+			}
 			c.Start()
 		}()
 	}
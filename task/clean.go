@@ -2,34 +2,44 @@ package task
 
 import (
 	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/events"
 
 	"go.uber.org/zap"
 )
 
 func Clean(logger *zap.Logger) {
+	var report events.CleanFinishedData
 	ids, err := db.DeduplicateGames()
 	if err != nil {
 		logger.Error("Failed to deduplicate games", zap.Error(err))
+		report.Errors = append(report.Errors, err.Error())
 	}
 	for _, id := range ids {
 		logger.Info("Deduplicated game", zap.Any("game_id", id))
 	}
+	report.Deduplicated = len(ids)
 	idmap, err := db.CleanOrphanGamesInGameInfos()
 	if err != nil {
 		logger.Error("Failed to clean orphan games", zap.Error(err))
+		report.Errors = append(report.Errors, err.Error())
 	}
 	for _, id := range idmap {
 		logger.Info("Cleaned orphan game in game info", zap.Any("in", id), zap.Any("removed", idmap[id]))
 	}
+	report.OrphanGames = len(idmap)
 	ids, err = db.CleanGameInfoWithEmptyGameIDs()
 	if err != nil {
 		logger.Error("Failed to clean game info with empty game ids", zap.Error(err))
+		report.Errors = append(report.Errors, err.Error())
 	}
 	for _, id := range ids {
 		logger.Info("Cleaned game info with empty game ids", zap.Any("game_id", id))
 	}
+	report.EmptyInfos = len(ids)
 	err = db.MergeSameNameGameInfos()
 	if err != nil {
 		logger.Error("Failed to merge same name game infos", zap.Error(err))
+		report.Errors = append(report.Errors, err.Error())
 	}
+	events.Publish(events.CleanFinished, report)
 }
@@ -3,9 +3,11 @@ package task
 import (
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/nitezs/pcgamedb/config"
 	"github.com/nitezs/pcgamedb/crawler"
+	"github.com/nitezs/pcgamedb/events"
 	"github.com/nitezs/pcgamedb/model"
 	"github.com/nitezs/pcgamedb/utils"
 
@@ -14,24 +16,36 @@ import (
 
 func Crawl(logger *zap.Logger) {
 	var games []*model.GameItem
+	var failed []string
 	var crawlerMap = crawler.BuildCrawlerMap(logger)
-	for _, item := range crawlerMap {
+	start := time.Now()
+	names := make([]string, 0, len(crawlerMap))
+	for source := range crawlerMap {
+		names = append(names, source)
+	}
+	events.Publish(events.CrawlStarted, events.CrawlStartedData{Crawlers: names})
+	for source, item := range crawlerMap {
 		logger.Info("Crawling", zap.String("crawler", item.Name()))
+		var g []*model.GameItem
+		var err error
 		if c, ok := item.(crawler.PagedCrawler); ok {
-			g, err := c.CrawlMulti([]int{1, 2, 3})
-			if err != nil {
-				logger.Warn("Failed to crawl games", zap.String("crawler", c.Name()), zap.Error(err))
-			}
-			games = append(games, g...)
+			g, err = c.CrawlMulti([]int{1, 2, 3})
 		} else if c, ok := item.(crawler.SimpleCrawler); ok {
-			g, err := c.CrawlAll()
-			if err != nil {
-				logger.Warn("Failed to crawl games", zap.String("crawler", c.Name()), zap.Error(err))
-			}
-			games = append(games, g...)
+			g, err = c.CrawlAll()
+		}
+		if err != nil {
+			logger.Warn("Failed to crawl games", zap.String("crawler", item.Name()), zap.Error(err))
+			failed = append(failed, source)
+			events.Publish(events.SourceFailed, events.SourceFailedData{Source: source, Error: err.Error()})
 		}
+		games = append(games, g...)
 	}
 	logger.Info("Crawled finished", zap.Int("count", len(games)))
+	events.Publish(events.CrawlFinished, events.CrawlFinishedData{
+		Count:    len(games),
+		Failed:   failed,
+		Duration: time.Since(start).Round(time.Second).String(),
+	})
 	for _, game := range games {
 		logger.Info(
 			"Crawled game",
@@ -41,6 +55,9 @@ func Crawl(logger *zap.Logger) {
 		)
 	}
 	Clean(logger)
+	if len(config.Config.Webhooks.CrawlTask) > 0 {
+		logger.Warn("webhooks.crawl_task is deprecated, subscribe a webhook to item-created and crawl-finished instead")
+	}
 	for _, u := range config.Config.Webhooks.CrawlTask {
 		_, err := url.Parse(u)
 		if err != nil {
@@ -0,0 +1,18 @@
+package task
+
+import (
+	"github.com/nitezs/pcgamedb/config"
+	"github.com/nitezs/pcgamedb/crawler"
+
+	"go.uber.org/zap"
+)
+
+// ImportSteamApps refreshes the local Steam app list from config.Config.SteamAppList or the Steam API
+func ImportSteamApps(logger *zap.Logger) {
+	count, err := crawler.ImportSteamApps(config.Config.SteamAppList)
+	if err != nil {
+		logger.Error("Failed to import Steam app list", zap.Error(err))
+		return
+	}
+	logger.Info("Imported Steam app list", zap.Int("count", count))
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -83,3 +84,27 @@ func SubSizeStrings(sizes []string) (string, error) {
 	}
 	return FormatSize(size), nil
 }
+
+var sizeRegex = regexp.MustCompile(`(?i)^\s*([0-9]+(?:[.,][0-9]+)?)\s*(TB|GB|MB|KB|B)?\s*$`)
+
+func ParseSize(sizeStr string) (int64, error) {
+	match := sizeRegex.FindStringSubmatch(sizeStr)
+	if match == nil {
+		return 0, fmt.Errorf("invalid size: %s", sizeStr)
+	}
+	size, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToUpper(match[2]) {
+	case "TB":
+		size *= 1024 * 1024 * 1024 * 1024
+	case "GB":
+		size *= 1024 * 1024 * 1024
+	case "MB":
+		size *= 1024 * 1024
+	case "KB":
+		size *= 1024
+	}
+	return int64(size), nil
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TrigramIndex is an in-memory index for typo tolerant name lookups.
+// Each key can be indexed under several names (e.g. a title and its aliases).
+type TrigramIndex[K comparable] struct {
+	mu       sync.RWMutex
+	postings map[string]map[K]struct{}
+	entries  map[K][]trigramName
+}
+
+type trigramName struct {
+	name       string
+	normalized string
+	grams      map[string]struct{}
+}
+
+type TrigramMatch[K comparable] struct {
+	Key     K
+	Name    string
+	Matched string
+	Score   float64
+}
+
+func NewTrigramIndex[K comparable]() *TrigramIndex[K] {
+	return &TrigramIndex[K]{
+		postings: make(map[string]map[K]struct{}),
+		entries:  make(map[K][]trigramName),
+	}
+}
+
+// Add indexes key under names, replacing any names previously indexed for key.
+// The first name is reported as the match name.
+func (idx *TrigramIndex[K]) Add(key K, names ...string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(key)
+	var entry []trigramName
+	seen := make(map[string]struct{})
+	for _, name := range names {
+		normalized := NormalizeForIndex(name)
+		if normalized == "" {
+			continue
+		}
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		grams := trigrams(normalized)
+		for gram := range grams {
+			keys, ok := idx.postings[gram]
+			if !ok {
+				keys = make(map[K]struct{})
+				idx.postings[gram] = keys
+			}
+			keys[key] = struct{}{}
+		}
+		entry = append(entry, trigramName{name: name, normalized: normalized, grams: grams})
+	}
+	if len(entry) > 0 {
+		idx.entries[key] = entry
+	}
+}
+
+func (idx *TrigramIndex[K]) Remove(key K) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(key)
+}
+
+func (idx *TrigramIndex[K]) remove(key K) {
+	for _, name := range idx.entries[key] {
+		for gram := range name.grams {
+			keys := idx.postings[gram]
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(idx.postings, gram)
+			}
+		}
+	}
+	delete(idx.entries, key)
+}
+
+func (idx *TrigramIndex[K]) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Search returns up to limit keys whose names score at least minScore against query,
+// best match first.
+func (idx *TrigramIndex[K]) Search(query string, limit int, minScore float64) []TrigramMatch[K] {
+	normalized := NormalizeForIndex(query)
+	if normalized == "" || limit <= 0 {
+		return nil
+	}
+	queryGrams := trigrams(normalized)
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	hits := make(map[K]int)
+	for gram := range queryGrams {
+		for key := range idx.postings[gram] {
+			hits[key]++
+		}
+	}
+	candidates := make([]K, 0, len(hits))
+	for key := range hits {
+		candidates = append(candidates, key)
+	}
+	// only rerank the keys sharing the most trigrams with the query
+	sort.Slice(candidates, func(i, j int) bool {
+		return hits[candidates[i]] > hits[candidates[j]]
+	})
+	if maxCandidates := limit * 20; len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	matches := make([]TrigramMatch[K], 0, len(candidates))
+	for _, key := range candidates {
+		entry := idx.entries[key]
+		best := TrigramMatch[K]{Key: key, Name: entry[0].name}
+		for _, name := range entry {
+			score := trigramScore(normalized, queryGrams, name)
+			if score > best.Score {
+				best.Score = score
+				best.Matched = name.name
+			}
+		}
+		if best.Score >= minScore {
+			matches = append(matches, best)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return len(matches[i].Matched) < len(matches[j].Matched)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func trigramScore(query string, queryGrams map[string]struct{}, name trigramName) float64 {
+	shared := 0
+	for gram := range queryGrams {
+		if _, ok := name.grams[gram]; ok {
+			shared++
+		}
+	}
+	dice := 2 * float64(shared) / float64(len(queryGrams)+len(name.grams))
+	score := 0.6*dice + 0.4*Similarity(query, name.normalized)
+	if strings.HasPrefix(name.normalized, query) {
+		score += 0.2
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score
+}
+
+func trigrams(normalized string) map[string]struct{} {
+	runes := []rune("  " + normalized + " ")
+	grams := make(map[string]struct{}, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])] = struct{}{}
+	}
+	return grams
+}
+
+// NormalizeForIndex lowercases name and collapses everything that is not a letter or digit into single spaces.
+func NormalizeForIndex(name string) string {
+	var builder strings.Builder
+	space := true
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			space = false
+		} else if !space {
+			builder.WriteRune(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNormalizeForIndex(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Hollow Knight", want: "hollow knight"},
+		{name: "  The Witcher 3: Wild Hunt  ", want: "the witcher 3 wild hunt"},
+		{name: "Baldur's Gate 3", want: "baldur s gate 3"},
+		{name: "S.T.A.L.K.E.R. 2", want: "s t a l k e r 2"},
+		{name: "Ведьмак 3", want: "ведьмак 3"},
+		{name: "---", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeForIndex(tt.name); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "doom", want: 4},
+		{a: "doom", b: "", want: 4},
+		{a: "doom", b: "DOOM", want: 0},
+		{a: "hollow knight", b: "holow knight", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "ведьмак", b: "ведьма", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := LevenshteinDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func testTrigramIndex() *TrigramIndex[int] {
+	idx := NewTrigramIndex[int]()
+	idx.Add(1, "Hollow Knight", "Hollow Knight: Voidheart Edition")
+	idx.Add(2, "Hollow Knight: Silksong")
+	idx.Add(3, "The Witcher 3: Wild Hunt", "Ведьмак 3: Дикая Охота")
+	idx.Add(4, "Hades")
+	idx.Add(5, "Hades II")
+	idx.Add(6, "Stardew Valley")
+	return idx
+}
+
+func TestTrigramIndexSearch(t *testing.T) {
+	idx := testTrigramIndex()
+	tests := []struct {
+		name    string
+		query   string
+		want    int
+		matched string
+	}{
+		{name: "exact", query: "Hollow Knight", want: 1, matched: "Hollow Knight"},
+		{name: "typo", query: "holow knigt", want: 1, matched: "Hollow Knight"},
+		{name: "prefix", query: "silksong", want: 2, matched: "Hollow Knight: Silksong"},
+		{name: "alias", query: "ведьмак 3", want: 3, matched: "Ведьмак 3: Дикая Охота"},
+		{name: "shorter name first", query: "hades", want: 4, matched: "Hades"},
+		{name: "sequel", query: "hades ii", want: 5, matched: "Hades II"},
+		{name: "transposed letters", query: "stardwe valley", want: 6, matched: "Stardew Valley"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := idx.Search(tt.query, 3, 0.3)
+			if len(matches) == 0 {
+				t.Fatalf("got no matches, want %d", tt.want)
+			}
+			best := matches[0]
+			if best.Key != tt.want || best.Matched != tt.matched {
+				t.Errorf("got %d (%q), want %d (%q)", best.Key, best.Matched, tt.want, tt.matched)
+			}
+			for i := 1; i < len(matches); i++ {
+				if matches[i].Score > matches[i-1].Score {
+					t.Errorf("matches are not sorted by score: %+v", matches)
+				}
+			}
+		})
+	}
+}
+
+func TestTrigramIndexSearchLimits(t *testing.T) {
+	idx := testTrigramIndex()
+	tests := []struct {
+		name     string
+		query    string
+		limit    int
+		minScore float64
+		want     int
+	}{
+		{name: "limit", query: "hollow knight", limit: 1, minScore: 0, want: 1},
+		{name: "both matches", query: "hollow knight", limit: 10, minScore: 0.5, want: 2},
+		{name: "min score", query: "hollow knight", limit: 10, minScore: 0.999, want: 1},
+		{name: "no shared trigram", query: "xyz", limit: 10, minScore: 0, want: 0},
+		{name: "empty query", query: " - ", limit: 10, minScore: 0, want: 0},
+		{name: "zero limit", query: "hades", limit: 0, minScore: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.Search(tt.query, tt.limit, tt.minScore); len(got) != tt.want {
+				t.Errorf("got %d matches %+v, want %d", len(got), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrigramIndexAddRemove(t *testing.T) {
+	idx := testTrigramIndex()
+	if got := idx.Len(); got != 6 {
+		t.Fatalf("got %d keys, want 6", got)
+	}
+
+	// adding a key again replaces its names
+	idx.Add(6, "Terraria")
+	if matches := idx.Search("stardew valley", 10, 0.5); len(matches) != 0 {
+		t.Errorf("got %+v for a replaced name, want none", matches)
+	}
+	if matches := idx.Search("terraria", 10, 0.5); len(matches) != 1 || matches[0].Key != 6 {
+		t.Errorf("got %+v, want key 6", matches)
+	}
+
+	idx.Remove(4)
+	for _, m := range idx.Search("hades", 10, 0) {
+		if m.Key == 4 {
+			t.Errorf("got removed key in %+v", m)
+		}
+	}
+	if got := idx.Len(); got != 5 {
+		t.Errorf("got %d keys, want 5", got)
+	}
+
+	// names without letters or digits are not indexed
+	idx.Add(7, "!!!")
+	if got := idx.Len(); got != 5 {
+		t.Errorf("got %d keys after adding an empty name, want 5", got)
+	}
+}
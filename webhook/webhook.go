@@ -0,0 +1,352 @@
+// Package webhook delivers events to the webhook subscriptions stored in the database.
+//
+// Every delivery is a POST of the JSON event with these headers:
+//
+//	X-Pcgamedb-Event:     event type, e.g. item-created
+//	X-Pcgamedb-Delivery:  delivery ID, the same on retries
+//	X-Pcgamedb-Timestamp: unix time of the attempt
+//	X-Pcgamedb-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret>
+//
+// Failed deliveries are retried with exponential backoff and every attempt is kept in the delivery log.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nitezs/pcgamedb/db"
+	"github.com/nitezs/pcgamedb/events"
+	"github.com/nitezs/pcgamedb/log"
+	"github.com/nitezs/pcgamedb/model"
+	"github.com/nitezs/pcgamedb/notify"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	maxAttempts   = 6
+	baseBackoff   = 30 * time.Second
+	claimLease    = 2 * time.Minute
+	retryInterval = 30 * time.Second
+	retryBatch    = 50
+	hooksTTL      = time.Minute
+	queueSize     = 1024
+	queueWorkers  = 4
+)
+
+var (
+	initOnce = &sync.Once{}
+	// client only connects to public addresses, webhook URLs are given by users
+	client  = notify.NewPublicClient()
+	queue   = make(chan *events.Event, queueSize)
+	pending = &sync.WaitGroup{}
+
+	hooksMutx     = &sync.Mutex{}
+	hooks         []*model.Webhook
+	hooksLoadedAt time.Time
+)
+
+type payload struct {
+	Event events.Type `json:"event"`
+	Time  time.Time   `json:"time"`
+	Data  any         `json:"data"`
+}
+
+// Init turns the events published by this process into deliveries.
+// Events are queued for a few workers so that publishing does not wait for the database,
+// deliveries are attempted right away and Run retries the failed ones.
+func Init() {
+	initOnce.Do(func() {
+		for i := 0; i < queueWorkers; i++ {
+			go func() {
+				for e := range queue {
+					enqueue(e)
+					pending.Done()
+				}
+			}()
+		}
+		events.AddHook(func(e *events.Event) {
+			pending.Add(1)
+			select {
+			case queue <- e:
+			default:
+				pending.Done()
+				log.Logger.Warn("Webhook queue is full, dropping event", zap.String("event", string(e.Type)))
+			}
+		})
+	})
+}
+
+// Wait blocks until the deliveries of the published events are saved,
+// the attempts that are still running are retried by Run
+func Wait() {
+	pending.Wait()
+}
+
+// Run retries due deliveries until ctx is done
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deliveries, err := db.GetDueWebhookDeliveries(retryBatch)
+			if err != nil {
+				log.Logger.Warn("Failed to get due webhook deliveries", zap.Error(err))
+				continue
+			}
+			for _, delivery := range deliveries {
+				attempt(delivery.ID)
+			}
+		}
+	}
+}
+
+// Invalidate drops the cached subscriptions after they were changed
+func Invalidate() {
+	hooksMutx.Lock()
+	defer hooksMutx.Unlock()
+	hooks = nil
+	hooksLoadedAt = time.Time{}
+}
+
+func activeHooks() ([]*model.Webhook, error) {
+	hooksMutx.Lock()
+	defer hooksMutx.Unlock()
+	if hooks != nil && time.Since(hooksLoadedAt) < hooksTTL {
+		return hooks, nil
+	}
+	all, err := db.GetAllWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	hooks = make([]*model.Webhook, 0, len(all))
+	for _, hook := range all {
+		if hook.Active {
+			hooks = append(hooks, hook)
+		}
+	}
+	hooksLoadedAt = time.Now()
+	return hooks, nil
+}
+
+func enqueue(e *events.Event) {
+	active, err := activeHooks()
+	if err != nil {
+		log.Logger.Warn("Failed to load webhooks", zap.Error(err))
+		return
+	}
+	var matched []*model.Webhook
+	for _, hook := range active {
+		if len(hook.Events) == 0 || slices.Contains(hook.Events, string(e.Type)) {
+			matched = append(matched, hook)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+	data, err := json.Marshal(payload{Event: e.Type, Time: e.Time, Data: e.Data})
+	if err != nil {
+		log.Logger.Warn("Failed to encode webhook payload", zap.String("event", string(e.Type)), zap.Error(err))
+		return
+	}
+	var authors []string
+	authorsLoaded := false
+	for _, hook := range matched {
+		if len(hook.Authors) > 0 {
+			if !authorsLoaded {
+				authors = eventAuthors(e)
+				authorsLoaded = true
+			}
+			// events without an author, like crawl and clean reports, go to every subscription
+			if authors != nil && !matchAuthors(hook.Authors, authors) {
+				continue
+			}
+		}
+		delivery := &model.WebhookDelivery{
+			WebhookID:     hook.ID,
+			Event:         string(e.Type),
+			Payload:       string(data),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		}
+		if err := db.SaveWebhookDelivery(delivery); err != nil {
+			log.Logger.Warn("Failed to save webhook delivery", zap.String("url", hook.URL), zap.Error(err))
+			continue
+		}
+		go attempt(delivery.ID)
+	}
+}
+
+// eventAuthors returns the authors an event is about, nil if it is not about any author
+func eventAuthors(e *events.Event) []string {
+	switch data := e.Data.(type) {
+	case model.GameItem:
+		return []string{data.Author}
+	case events.SourceFailedData:
+		return []string{data.Source}
+	case events.InfoOrganizedData:
+		items, err := db.GetGameItemsByIDs(data.GameIDs)
+		if err != nil {
+			log.Logger.Warn("Failed to get organized game items", zap.Error(err))
+			return []string{}
+		}
+		authors := make([]string, 0, len(items))
+		for _, item := range items {
+			authors = append(authors, item.Author)
+		}
+		return authors
+	default:
+		return nil
+	}
+}
+
+func matchAuthors(filter []string, authors []string) bool {
+	for _, f := range filter {
+		for _, author := range authors {
+			if strings.EqualFold(f, author) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attempt sends a delivery if it is due and no other worker has claimed it
+func attempt(id primitive.ObjectID) {
+	delivery, err := db.ClaimWebhookDelivery(id, claimLease)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Logger.Warn("Failed to claim webhook delivery", zap.Error(err))
+		}
+		return
+	}
+	hook, err := db.GetWebhookByID(delivery.WebhookID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.Error = "webhook was deleted"
+			_ = db.SaveWebhookDelivery(delivery)
+		}
+		return
+	}
+	send(hook, delivery)
+}
+
+func send(hook *model.Webhook, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+	delivery.ResponseCode, delivery.Error = post(hook, delivery)
+	switch {
+	case delivery.Error == "":
+		delivery.Status = model.WebhookDeliverySuccess
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = model.WebhookDeliveryFailed
+	default:
+		delivery.Status = model.WebhookDeliveryPending
+		delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts))
+	}
+	if err := db.SaveWebhookDelivery(delivery); err != nil {
+		log.Logger.Warn("Failed to save webhook delivery", zap.String("url", hook.URL), zap.Error(err))
+	}
+	if delivery.Error != "" {
+		log.Logger.Warn("Webhook delivery failed",
+			zap.String("url", hook.URL),
+			zap.String("event", delivery.Event),
+			zap.Int("attempts", delivery.Attempts),
+			zap.String("error", delivery.Error),
+		)
+	}
+}
+
+// backoff is the delay before the next attempt: 30s, 1m, 2m, 4m...
+func backoff(attempts int) time.Duration {
+	return baseBackoff << (attempts - 1)
+}
+
+func post(hook *model.Webhook, delivery *model.WebhookDelivery) (int, string) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pcgamedb-webhook")
+	req.Header.Set("X-Pcgamedb-Event", delivery.Event)
+	req.Header.Set("X-Pcgamedb-Delivery", delivery.ID.Hex())
+	req.Header.Set("X-Pcgamedb-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Pcgamedb-Signature", "sha256="+Sign(hook.Secret, timestamp, body))
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, ""
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random secret for a new webhook
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Redeliver sends the payload of a logged delivery of the webhook again as a new delivery
+func Redeliver(webhookID primitive.ObjectID, id primitive.ObjectID) (*model.WebhookDelivery, error) {
+	old, err := db.GetWebhookDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if old.WebhookID != webhookID {
+		return nil, mongo.ErrNoDocuments
+	}
+	hook, err := db.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+	delivery := &model.WebhookDelivery{
+		WebhookID:     old.WebhookID,
+		Event:         old.Event,
+		Payload:       old.Payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: time.Now().Add(claimLease),
+	}
+	if err := db.SaveWebhookDelivery(delivery); err != nil {
+		return nil, err
+	}
+	send(hook, delivery)
+	return delivery, nil
+}
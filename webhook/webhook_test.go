@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"item-created"}`)
+	// expected signatures computed with: printf '<timestamp>.<body>' | openssl dgst -sha256 -hmac <secret>
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      string
+	}{
+		{name: "event", secret: "secret", timestamp: 1700000000, body: body, want: "a5aa496a1cff3900259eb1b54477f03bde9192408cca8bf66327cef1d1711812"},
+		{name: "empty body", secret: "secret", timestamp: 0, want: "3445798a051818ef95def46c2eb62b43d377ce6e3c29b4d0aec3da0e59577f79"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignChanges(t *testing.T) {
+	body := []byte(`{"event":"item-created"}`)
+	base := Sign("secret", 1700000000, body)
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+	}{
+		{name: "secret", secret: "other", timestamp: 1700000000, body: body},
+		{name: "timestamp", secret: "secret", timestamp: 1700000001, body: body},
+		{name: "body", secret: "secret", timestamp: 1700000000, body: []byte(`{"event":"item-updated"}`)},
+		// the separator keeps the timestamp and body apart
+		{name: "shifted digit", secret: "secret", timestamp: 170000000, body: append([]byte("0"), body...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.body); got == base {
+				t.Errorf("got the same signature %s for a different %s", got, tt.name)
+			}
+		})
+	}
+}